@@ -0,0 +1,433 @@
+package art
+
+import (
+	"bytes"
+	"cmp"
+	"iter"
+	"unsafe"
+)
+
+// Aggregator summarizes values. Combine must be associative, with Empty as
+// identity, and is always given the summaries in key order.
+type Aggregator[V, S any] interface {
+	Empty() S
+	FromValue(V) S
+	Combine(S, S) S
+}
+
+// IncrementalAggregator is an Aggregator whose Combine is also commutative
+// and which can remove a value from a summary, reporting false when it
+// can't, e.g. when removing the minimum. An AggregateTree then updates the
+// summaries above a changed key without combining the children of the nodes
+// again, unless Remove fails.
+type IncrementalAggregator[V, S any] interface {
+	Aggregator[V, S]
+	Remove(S, V) (S, bool)
+}
+
+type number interface {
+	ints | uints | floats
+}
+
+// SumAggregator sums the values. The float sums are updated by subtracting
+// the removed values, which accumulates rounding errors.
+type SumAggregator[V number] struct{}
+
+func (SumAggregator[V]) Empty() V         { return 0 }
+func (SumAggregator[V]) FromValue(v V) V  { return v }
+func (SumAggregator[V]) Combine(a, b V) V { return a + b }
+
+func (SumAggregator[V]) Remove(s, v V) (V, bool) { return s - v, true }
+
+// CountAggregator counts the values.
+type CountAggregator[V any] struct{}
+
+func (CountAggregator[V]) Empty() int           { return 0 }
+func (CountAggregator[V]) FromValue(V) int      { return 1 }
+func (CountAggregator[V]) Combine(a, b int) int { return a + b }
+
+func (CountAggregator[V]) Remove(s int, _ V) (int, bool) { return s - 1, true }
+
+// MinMax is the summary of MinMaxAggregator. Min and Max are only meaningful
+// when Count is positive.
+type MinMax[V cmp.Ordered] struct {
+	Min, Max V
+	Count    int
+}
+
+// MinMaxAggregator keeps the minimum, the maximum and the count of the values.
+type MinMaxAggregator[V cmp.Ordered] struct{}
+
+func (MinMaxAggregator[V]) Empty() MinMax[V] { return MinMax[V]{} }
+
+func (MinMaxAggregator[V]) FromValue(v V) MinMax[V] { return MinMax[V]{v, v, 1} }
+
+func (MinMaxAggregator[V]) Combine(a, b MinMax[V]) MinMax[V] {
+	switch {
+	case a.Count == 0:
+		return b
+	case b.Count == 0:
+		return a
+	}
+	return MinMax[V]{min(a.Min, b.Min), max(a.Max, b.Max), a.Count + b.Count}
+}
+
+// Remove can't remove the minimum or the maximum, which may be the only one.
+func (MinMaxAggregator[V]) Remove(s MinMax[V], v V) (MinMax[V], bool) {
+	switch {
+	case s.Count == 1:
+		return MinMax[V]{}, true
+	case v <= s.Min || v >= s.Max:
+		return s, false
+	}
+	s.Count--
+	return s, true
+}
+
+// AggregateTree is a Tree keeping a summary of the values below each of its
+// inner nodes.
+type AggregateTree[K nodeKey, V, S any] interface {
+	Tree[K, V]
+
+	// Aggregate returns the summary of the values whose key is within
+	// [start, end] in the order of the tree.
+	Aggregate(start, end K) S
+
+	// AggregatePrefix returns the summary of the values whose key starts with
+	// p. It panics when the tree has no prefix search, e.g. numeric trees.
+	AggregatePrefix(p K) S
+}
+
+// summarizedTree is implemented by the generated trees.
+type summarizedTree[K nodeKey, V any] interface {
+	Tree[K, V]
+
+	rootRef() nodeRef
+	encodeKey(K) []byte
+	leafKey(unsafe.Pointer) []byte
+	leafValue(unsafe.Pointer) V
+	setSummaries(summaries[V])
+}
+
+// summaries keeps the summaries of the inner nodes of a tree up to date. The
+// trees call it after changing a leaf, with the inner nodes above the leaf
+// from the root, which Insert and Delete walk anyway.
+type summaries[V any] interface {
+	// node summarizes a new inner node from its children.
+	node(ref nodeRef)
+
+	insert(path []pathNode, val V)
+	replace(path []pathNode, old, val V)
+	remove(path []pathNode, old V)
+}
+
+// pathNode is an inner node on the path of a changed leaf: the slot holding
+// it and the node it held before the change. Only the last node of the path
+// can be replaced, when adding or deleting a child grows, shrinks or
+// collapses it.
+type pathNode struct {
+	slot *nodeRef
+	ref  nodeRef
+}
+
+// NewAggregateTree returns an AggregateTree over the given tree, which must
+// be created by one of the binary, alpha, compound or UUID constructors and
+// can't be given to NewAggregateTree again. The AggregateTree keeps the
+// summary of the values below each inner node of the tree, which updates the
+// summaries along the path of the key on Insert and Delete. The queries
+// combine the summaries of the subtrees within the bounds, in O(key length)
+// for fixed-size keys.
+//
+// With an IncrementalAggregator, such as SumAggregator, CountAggregator and
+// MinMaxAggregator, the updates take constant time per node.
+//
+//	t := NewAggregateTree(NewSignedBinaryTree[int64, float64](), SumAggregator[float64]{})
+//	t.Insert(time.Now().Unix(), 0.5)
+//	total := t.Aggregate(from, to)
+func NewAggregateTree[K nodeKey, V, S any](inner Tree[K, V], agg Aggregator[V, S]) AggregateTree[K, V, S] {
+	st, ok := inner.(summarizedTree[K, V])
+	if !ok {
+		panic("art: NewAggregateTree needs a tree created by this package")
+	}
+
+	t := &aggregateTree[K, V, S]{inner: st, agg: agg, sums: make(map[unsafe.Pointer]S)}
+	t.inc, _ = agg.(IncrementalAggregator[V, S])
+	st.setSummaries(t)
+	t.summarizeAll(st.rootRef())
+	return t
+}
+
+type aggregateTree[K nodeKey, V, S any] struct {
+	inner summarizedTree[K, V]
+	agg   Aggregator[V, S]
+	inc   IncrementalAggregator[V, S] // agg, if incremental
+	// sums maps the inner nodes to the summary of their subtree.
+	sums map[unsafe.Pointer]S
+}
+
+func (t *aggregateTree[K, V, S]) Insert(key K, val V) { t.inner.Insert(key, val) }
+
+func (t *aggregateTree[K, V, S]) Delete(key K) bool { return t.inner.Delete(key) }
+
+func (t *aggregateTree[K, V, S]) Aggregate(start, end K) S {
+	lo, hi := t.inner.encodeKey(start), t.inner.encodeKey(end)
+	if bytes.Compare(lo, hi) > 0 {
+		return t.agg.Empty()
+	}
+	return t.aggregate(t.inner.rootRef(), 0, lo, hi, true, true)
+}
+
+func (t *aggregateTree[K, V, S]) AggregatePrefix(p K) S {
+	pe, ok := t.inner.(interface{ encodePrefix(K) []byte })
+	if !ok {
+		panic("art: AggregatePrefix needs a tree with prefix search")
+	}
+	prefix := pe.encodePrefix(p)
+
+	ref := t.inner.rootRef()
+	depth := 0
+	for ref.pointer != nil {
+		if ref.tag == nodeKindLeaf {
+			if bytes.HasPrefix(t.inner.leafKey(ref.pointer), prefix) {
+				return t.summary(ref)
+			}
+			break
+		}
+
+		if ref.node().prefixLen > 0 {
+			nodePrefix := t.prefix(ref, depth)
+			n := min(len(nodePrefix), len(prefix)-depth)
+			if !bytes.Equal(nodePrefix[:n], prefix[depth:depth+n]) {
+				break
+			}
+			depth += len(nodePrefix)
+		}
+
+		if depth >= len(prefix) {
+			return t.summary(ref)
+		}
+
+		child := ref.findChild(prefix[depth])
+		if child == nil {
+			break
+		}
+		ref = *child
+		depth++
+	}
+
+	return t.agg.Empty()
+}
+
+// aggregate combines the summaries of the leaves below ref whose key is
+// within [lo, hi]. loOn (resp. hiOn) is set while the path still follows lo
+// (resp. hi); the subtrees off both bounds use their stored summary.
+func (t *aggregateTree[K, V, S]) aggregate(ref nodeRef, depth int, lo, hi []byte, loOn, hiOn bool) S {
+	if ref.pointer == nil {
+		return t.agg.Empty()
+	}
+	if !loOn && !hiOn {
+		return t.summary(ref)
+	}
+
+	if ref.tag == nodeKindLeaf {
+		key := t.inner.leafKey(ref.pointer)
+		if (loOn && bytes.Compare(key, lo) < 0) || (hiOn && bytes.Compare(key, hi) > 0) {
+			return t.agg.Empty()
+		}
+		return t.summary(ref)
+	}
+
+	if ref.node().prefixLen > 0 {
+		prefix := t.prefix(ref, depth)
+		if loOn {
+			bound := lo[depth:min(len(lo), depth+len(prefix))]
+			switch c := bytes.Compare(prefix[:len(bound)], bound); {
+			case c < 0:
+				return t.agg.Empty()
+			case c > 0 || len(bound) < len(prefix):
+				loOn = false
+			}
+		}
+		if hiOn {
+			bound := hi[depth:min(len(hi), depth+len(prefix))]
+			switch c := bytes.Compare(prefix[:len(bound)], bound); {
+			case c > 0 || (c == 0 && len(bound) < len(prefix)):
+				return t.agg.Empty()
+			case c < 0:
+				hiOn = false
+			}
+		}
+		depth += len(prefix)
+
+		if !loOn && !hiOn {
+			return t.summary(ref)
+		}
+	}
+
+	// the keys below are longer than the bounds ending here
+	if loOn && depth >= len(lo) {
+		loOn = false
+	}
+	if hiOn && depth >= len(hi) {
+		return t.agg.Empty()
+	}
+
+	var loB, hiB byte = 0, 255
+	if loOn {
+		loB = lo[depth]
+	}
+	if hiOn {
+		hiB = hi[depth]
+	}
+
+	s := t.agg.Empty()
+	ref.eachChild(loB, hiB, func(b byte, child nodeRef) {
+		sub := t.aggregate(child, depth+1, lo, hi, loOn && b == loB, hiOn && b == hiB)
+		s = t.agg.Combine(s, sub)
+	})
+	return s
+}
+
+// summarizeAll summarizes the inner nodes below ref, the children first.
+func (t *aggregateTree[K, V, S]) summarizeAll(ref nodeRef) {
+	if ref.pointer == nil || ref.tag == nodeKindLeaf {
+		return
+	}
+
+	ref.eachChild(0, 255, func(_ byte, child nodeRef) { t.summarizeAll(child) })
+	t.node(ref)
+}
+
+func (t *aggregateTree[K, V, S]) node(ref nodeRef) {
+	s := t.agg.Empty()
+	ref.eachChild(0, 255, func(_ byte, child nodeRef) {
+		s = t.agg.Combine(s, t.summary(child))
+	})
+	t.sums[ref.pointer] = s
+}
+
+func (t *aggregateTree[K, V, S]) insert(path []pathNode, val V) {
+	t.moved(path, false)
+	if t.inc == nil {
+		t.resummarize(path)
+		return
+	}
+
+	add := t.agg.FromValue(val)
+	for _, p := range path {
+		t.sums[p.slot.pointer] = t.agg.Combine(t.sums[p.slot.pointer], add)
+	}
+}
+
+func (t *aggregateTree[K, V, S]) replace(path []pathNode, old, val V) {
+	if t.inc == nil {
+		t.resummarize(path)
+		return
+	}
+
+	add := t.agg.FromValue(val)
+	for i := len(path) - 1; i >= 0; i-- {
+		ref := *path[i].slot
+		if s, ok := t.inc.Remove(t.sums[ref.pointer], old); ok {
+			t.sums[ref.pointer] = t.agg.Combine(s, add)
+		} else {
+			t.node(ref) // the children are up to date
+		}
+	}
+}
+
+func (t *aggregateTree[K, V, S]) remove(path []pathNode, old V) {
+	if t.moved(path, true) {
+		path = path[:len(path)-1]
+	}
+	if t.inc == nil {
+		t.resummarize(path)
+		return
+	}
+
+	for i := len(path) - 1; i >= 0; i-- {
+		ref := *path[i].slot
+		if s, ok := t.inc.Remove(t.sums[ref.pointer], old); ok {
+			t.sums[ref.pointer] = s
+		} else {
+			t.node(ref)
+		}
+	}
+}
+
+// moved moves the summary of the last node of the path when growing or
+// shrinking replaced it. It reports whether deleting a child collapsed the
+// node into its last child, which already has its summary.
+func (t *aggregateTree[K, V, S]) moved(path []pathNode, deleting bool) bool {
+	if len(path) == 0 {
+		return false
+	}
+
+	p := path[len(path)-1]
+	if p.slot.pointer == p.ref.pointer {
+		return false
+	}
+
+	s := t.sums[p.ref.pointer]
+	delete(t.sums, p.ref.pointer)
+	if deleting && p.ref.tag == nodeKind4 {
+		return true
+	}
+	t.sums[p.slot.pointer] = s
+	return false
+}
+
+// resummarize summarizes the nodes of the path again, bottom-up.
+func (t *aggregateTree[K, V, S]) resummarize(path []pathNode) {
+	for i := len(path) - 1; i >= 0; i-- {
+		t.node(*path[i].slot)
+	}
+}
+
+func (t *aggregateTree[K, V, S]) summary(ref nodeRef) S {
+	if ref.tag == nodeKindLeaf {
+		return t.agg.FromValue(t.inner.leafValue(ref.pointer))
+	}
+	return t.sums[ref.pointer]
+}
+
+// prefix returns the full compressed path of the inner node ref.
+func (t *aggregateTree[K, V, S]) prefix(ref nodeRef, depth int) []byte {
+	n := ref.node()
+	if n.prefixLen <= maxPrefixLen {
+		return n.prefix[:n.prefixLen]
+	}
+	return t.inner.leafKey(minimum[V](ref))[depth : depth+int(n.prefixLen)]
+}
+
+func (t *aggregateTree[K, V, S]) Search(key K) (V, bool) { return t.inner.Search(key) }
+
+func (t *aggregateTree[K, V, S]) Minimum() (K, V, bool) { return t.inner.Minimum() }
+
+func (t *aggregateTree[K, V, S]) Maximum() (K, V, bool) { return t.inner.Maximum() }
+
+func (t *aggregateTree[K, V, S]) All() iter.Seq2[K, V] { return t.inner.All() }
+
+func (t *aggregateTree[K, V, S]) Backward() iter.Seq2[K, V] { return t.inner.Backward() }
+
+func (t *aggregateTree[K, V, S]) Prefix(p K) iter.Seq2[K, V] { return t.inner.Prefix(p) }
+
+func (t *aggregateTree[K, V, S]) PrefixBackward(p K) iter.Seq2[K, V] {
+	return t.inner.PrefixBackward(p)
+}
+
+func (t *aggregateTree[K, V, S]) TopK(k uint) iter.Seq2[K, V] { return t.inner.TopK(k) }
+
+func (t *aggregateTree[K, V, S]) BottomK(k uint) iter.Seq2[K, V] { return t.inner.BottomK(k) }
+
+func (t *aggregateTree[K, V, S]) Range(start, end K) iter.Seq2[K, V] {
+	return t.inner.Range(start, end)
+}
+
+func (t *aggregateTree[K, V, S]) RangeWith(opts RangeOptions[K]) iter.Seq2[K, V] {
+	return t.inner.RangeWith(opts)
+}
+
+func (t *aggregateTree[K, V, S]) Size() int { return t.inner.Size() }
+
+func (t *aggregateTree[K, V, S]) Stats() Stats { return t.inner.Stats() }
@@ -0,0 +1,176 @@
+package art_test
+
+import (
+	"maps"
+	"math/rand/v2"
+	"strings"
+	"testing"
+
+	"github.com/Clement-Jean/go-art"
+)
+
+func TestAggregate(t *testing.T) {
+	r := rand.New(rand.NewPCG(13, 14))
+	sums := art.NewAggregateTree(art.NewSignedBinaryTree[int64, int](), art.SumAggregator[int]{})
+	extremes := art.NewAggregateTree(art.NewSignedBinaryTree[int64, int](), art.MinMaxAggregator[int]{})
+	firsts := art.NewAggregateTree(art.NewSignedBinaryTree[int64, int](), firstAggregator{})
+	model := map[int64]int{}
+
+	for i := range 5000 {
+		key := r.Int64N(4000) - 2000
+		if i%100 < 30 {
+			key *= 1 << 20 // long common prefixes
+		}
+
+		if r.IntN(3) == 0 {
+			_, ok := model[key]
+			if sums.Delete(key) != ok || extremes.Delete(key) != ok || firsts.Delete(key) != ok {
+				t.Fatalf("expected Delete(%d) to report %t", key, ok)
+			}
+			maps.DeleteFunc(model, func(k int64, _ int) bool { return k == key })
+			continue
+		}
+
+		val := r.IntN(1000) - 500
+		sums.Insert(key, val)
+		extremes.Insert(key, val)
+		firsts.Insert(key, val)
+		model[key] = val
+
+		if i%50 != 0 {
+			continue
+		}
+
+		start := r.Int64N(5000) - 2500
+		end := start + r.Int64N(2000)
+		if i%150 == 0 {
+			start, end = -1<<40, 1<<40
+		}
+
+		sum, mm := 0, art.MinMax[int]{Min: 1000, Max: -1000}
+		fk, f := end+1, first{}
+		for k, v := range model {
+			if k >= start && k <= end {
+				sum += v
+				mm.Min, mm.Max, mm.Count = min(mm.Min, v), max(mm.Max, v), mm.Count+1
+				if k < fk {
+					fk, f = k, first{v, true}
+				}
+			}
+		}
+
+		if got := sums.Aggregate(start, end); got != sum {
+			t.Fatalf("expected the sum over [%d, %d] to be %d, got %d", start, end, sum, got)
+		}
+		got := extremes.Aggregate(start, end)
+		if got.Count != mm.Count || (mm.Count > 0 && got != mm) {
+			t.Fatalf("expected %v over [%d, %d], got %v", mm, start, end, got)
+		}
+		if got := firsts.Aggregate(start, end); got != f {
+			t.Fatalf("expected the first value %v over [%d, %d], got %v", f, start, end, got)
+		}
+	}
+
+	if got := sums.Aggregate(10, -10); got != 0 {
+		t.Fatalf("expected an empty range to sum to 0, got %d", got)
+	}
+}
+
+func TestAggregatePrefix(t *testing.T) {
+	tree := art.NewAggregateTree(art.NewAlphaSortedTree[string, int](), art.CountAggregator[int]{})
+	long := strings.Repeat("metrics.", 3)
+
+	keys := []string{
+		long + "cpu.user", long + "cpu.system", long + "cpu", long + "disk.read",
+		"mem", "mem.free", "net.rx",
+	}
+	for i, k := range keys {
+		tree.Insert(k, i)
+	}
+	tree.Insert("mem", 42) // replacing a value
+
+	tests := []struct {
+		prefix   string
+		expected int
+	}{
+		{"", 7},
+		{"metrics", 4},
+		{long + "cpu", 3},
+		{long + "cpu.", 2},
+		{long + "disk.read", 1},
+		{long + "diskette", 0},
+		{"mem", 2},
+		{"net.tx", 0},
+	}
+
+	for _, tt := range tests {
+		if got := tree.AggregatePrefix(tt.prefix); got != tt.expected {
+			t.Fatalf("expected %d values under %q, got %d", tt.expected, tt.prefix, got)
+		}
+	}
+
+	tree.Delete(long + "cpu.user")
+	tree.Delete(long + "cpu.system")
+	if got := tree.AggregatePrefix(long + "cpu"); got != 1 {
+		t.Fatalf("expected 1 value after deleting, got %d", got)
+	}
+	if got := tree.Aggregate("a", "n"); got != 4 {
+		t.Fatalf("expected 4 values in [a, n], got %d", got)
+	}
+}
+
+// firstAggregator keeps the value of the smallest key, which depends on the
+// order of the summaries and isn't incremental.
+type firstAggregator struct{}
+
+type first struct {
+	value int
+	ok    bool
+}
+
+func (firstAggregator) Empty() first          { return first{} }
+func (firstAggregator) FromValue(v int) first { return first{v, true} }
+
+func (firstAggregator) Combine(a, b first) first {
+	if a.ok {
+		return a
+	}
+	return b
+}
+
+func TestAggregateFilledTree(t *testing.T) {
+	inner := art.NewUnsignedBinaryTree[uint32, int]()
+	for i := range 1000 {
+		inner.Insert(uint32(i*37%1000), i)
+	}
+
+	firsts := art.NewAggregateTree(inner, firstAggregator{})
+
+	// the inner tree keeps the summaries up to date
+	inner.Insert(5000, -1)
+	inner.Delete(0)
+	firsts.Insert(1, 42)
+
+	tests := []struct {
+		start, end uint32
+		expected   first
+	}{
+		{0, 10000, first{42, true}},
+		{2, 10, first{946, true}}, // 946 * 37 % 1000 == 2
+		{1000, 4999, first{}},
+		{1000, 6000, first{-1, true}},
+	}
+
+	for _, tt := range tests {
+		if got := firsts.Aggregate(tt.start, tt.end); got != tt.expected {
+			t.Fatalf("expected %v over [%d, %d], got %v", tt.expected, tt.start, tt.end, got)
+		}
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected a second AggregateTree over the same tree to panic")
+		}
+	}()
+	art.NewAggregateTree(inner, art.CountAggregator[int]{})
+}
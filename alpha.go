@@ -1,5 +1,101 @@
 package art
 
-func NewAlphaSortedTree[K chars, V any]() Tree[K, V] {
-	return &alphaSortedTree[K, V]{}
+// NewAlphaSortedTree returns a tree ordering the keys byte-wise, which is the
+// code point order for UTF-8 text, or the UTF-16 code unit order with
+// WithUTF16Order. []rune keys are stored as UTF-8, the invalid runes becoming
+// utf8.RuneError.
+func NewAlphaSortedTree[K chars | []rune, V any](opts ...Option) Tree[K, V] {
+	var k K
+	switch any(k).(type) {
+	case []rune:
+		return any(&convertedTree[[]rune, string, V]{
+			inner: newAlphaSortedTree[string, V](opts),
+			to:    func(r []rune) string { return string(r) },
+			from:  func(s string) []rune { return []rune(s) },
+		}).(Tree[K, V])
+	case []byte:
+		return any(newAlphaSortedTree[[]byte, V](opts)).(Tree[K, V])
+	}
+	return any(newAlphaSortedTree[string, V](opts)).(Tree[K, V])
+}
+
+func newAlphaSortedTree[K chars, V any](opts []Option) Tree[K, V] {
+	var k K
+	_, isBytes := any(k).([]byte)
+	o := newTreeOptions(treeOptions{keyCopy: isBytes}, opts)
+
+	if o.foldsText() {
+		// the tree only knows the folded keys
+		return &spellingTree[K, V]{
+			inner:   &alphaSortedTree[K, spelling[K, V]]{alphaCodec: alphaCodec[K]{opts: o}},
+			keyCopy: o.keyCopy,
+		}
+	}
+	return &alphaSortedTree[K, V]{alphaCodec: alphaCodec[K]{opts: o}}
+}
+
+// NewAlphaSortedSet returns a set ordering the keys like
+// NewAlphaSortedTree.
+// The folding options make it return the folded keys.
+func NewAlphaSortedSet[K chars](opts ...Option) Set[K] {
+	var k K
+	_, isBytes := any(k).([]byte)
+	t := &alphaSortedTree[K, struct{}]{
+		alphaCodec: alphaCodec[K]{opts: newTreeOptions(treeOptions{keyCopy: isBytes}, opts)},
+	}
+	return &keySet[K]{inner: t, encode: t.encodeKey}
+}
+
+// alphaCodec encodes the keys of the alpha trees.
+type alphaCodec[K chars] struct {
+	bck  AlphabeticalOrderKey[K]
+	opts treeOptions
+}
+
+// encodeKey transforms the key and appends the end byte making the keys
+// prefix-free. Binary keys are escaped instead.
+func (c *alphaCodec[K]) encodeKey(key K) []byte {
+	_, keyS := c.bck.Transform(key)
+	if c.opts.foldsText() {
+		keyS = c.opts.foldText(keyS)
+	}
+	if c.opts.utf16 {
+		keyS = utf16Order(keyS)
+	}
+	if c.opts.binaryKeys {
+		keyS = appendEscaped(make([]byte, 0, len(keyS)+2), keyS)
+		return append(keyS, escapeByte, endByte)
+	}
+	if c.opts.keyCopy {
+		// the full slice expression forces append to copy the caller's bytes
+		keyS = keyS[:len(keyS):len(keyS)]
+	}
+	return append(keyS, '\x00')
+}
+
+// encodePrefix transforms the prefix without the end byte.
+func (c *alphaCodec[K]) encodePrefix(p K) []byte {
+	_, prefix := c.bck.Transform(p)
+	if c.opts.foldsText() {
+		prefix = c.opts.foldText(prefix)
+	}
+	if c.opts.utf16 {
+		prefix = utf16Order(prefix)
+	}
+	if c.opts.binaryKeys {
+		return appendEscaped(nil, prefix)
+	}
+	return prefix
+}
+
+func (c *alphaCodec[K]) decodeKey(b []byte) K {
+	if c.opts.binaryKeys {
+		b, _ = unescape(b)
+	} else {
+		b = b[:len(b)-1] // drop end byte
+	}
+	if c.opts.utf16 {
+		b = codePointOrder(b)
+	}
+	return c.bck.Restore(b)
 }
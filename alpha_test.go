@@ -3,8 +3,10 @@ package art_test
 import (
 	"bufio"
 	"fmt"
+	"math/rand/v2"
 	"os"
 	"slices"
+	"strings"
 	"testing"
 
 	"github.com/Clement-Jean/go-art"
@@ -74,6 +76,30 @@ func TestAlphaInsert(t *testing.T) {
 	}
 }
 
+func TestAlphaSizePrefixSplit(t *testing.T) {
+	tests := []struct {
+		name string
+		keys []string
+	}{
+		{"short prefix", []string{"abcdef1", "abcdef2", "abX"}},
+		{"long prefix", []string{"abcdefghijklmnop1", "abcdefghijklmnop2", "abcdefghijkX"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tr := art.NewAlphaSortedTree[string, int]()
+			for i, k := range tt.keys {
+				tr.Insert(k, i)
+			}
+
+			// the last key splits the compressed path of the first two
+			if tr.Size() != len(tt.keys) {
+				t.Fatalf("expected size %d, got %d", len(tt.keys), tr.Size())
+			}
+		})
+	}
+}
+
 func TestAlphaInsertSearchWords(t *testing.T) {
 	tr := art.NewAlphaSortedTree[string, int]()
 
@@ -691,3 +717,268 @@ func TestAlphaRange(t *testing.T) {
 		})
 	}
 }
+
+func TestAlphaKeyCopy(t *testing.T) {
+	tr := art.NewAlphaSortedTree[[]byte, int]()
+	buf := make([]byte, 0, 16)
+
+	buf = append(buf[:0], "apple"...)
+	tr.Insert(buf, 1)
+	buf = append(buf[:0], "melon"...)
+	tr.Insert(buf, 2)
+
+	if _, ok := tr.Search([]byte("apple")); !ok {
+		t.Fatal("expected apple to survive the reuse of the buffer")
+	}
+
+	var got []string
+	for k, _ := range tr.All() {
+		got = append(got, string(k))
+	}
+
+	if expected := []string{"apple", "melon"}; !slices.Equal(got, expected) {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+}
+
+func TestAlphaKeyCheck(t *testing.T) {
+	tr := art.NewAlphaSortedTree[[]byte, int](art.WithKeyCopy(false), art.WithKeyCheck())
+	key := make([]byte, 5, 6)
+
+	copy(key, "apple")
+	tr.Insert(key, 1)
+	tr.Insert([]byte("melon"), 2)
+
+	if _, ok := tr.Search([]byte("apple")); !ok {
+		t.Fatal("expected apple to be found")
+	}
+
+	copy(key, "grape")
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected the modified key to be detected")
+		}
+	}()
+
+	for range tr.All() {
+	}
+}
+
+func TestAlphaRangeWords(t *testing.T) {
+	words := loadTestFile("testdata/words.txt")
+	tr := art.NewAlphaSortedTree[[]byte, int]()
+
+	var keys []string
+	for i, w := range words {
+		tr.Insert(w, i)
+		keys = append(keys, string(w))
+	}
+
+	slices.Sort(keys)
+	keys = slices.Compact(keys)
+
+	bounds := [][2]string{
+		{"a", "ab"},
+		{"abac", "abad"},
+		{"cat", "catz"},
+		{"hel", "help"},
+		{"zebra", "zz"},
+		{"Q", "R"},
+		{"electr", "electro"},
+	}
+
+	for _, b := range bounds {
+		lo, _ := slices.BinarySearch(keys, b[0])
+		hi, found := slices.BinarySearch(keys, b[1])
+		if found {
+			hi++
+		}
+
+		var got []string
+		for k, _ := range tr.Range([]byte(b[0]), []byte(b[1])) {
+			got = append(got, string(k))
+		}
+
+		if !slices.Equal(keys[lo:hi], got) {
+			t.Fatalf("range %q: expected %d keys, got %d", b, hi-lo, len(got))
+		}
+	}
+}
+
+func TestAlphaPrefixCompressedPath(t *testing.T) {
+	keys := []string{
+		"this:key:has:a:long:common:prefix:1",
+		"this:key:has:a:long:common:prefix:2",
+		"this:key:has:a:long:prefix:3",
+		"other",
+	}
+
+	tests := []struct {
+		prefix   string
+		expected []string
+	}{
+		{"this:key:has:a:lo", keys[:3]},
+		{"this:key:has:a:long:common:pre", keys[:2]},
+		{"this:key:has:a:long:p", keys[2:3]},
+		{"this:key:hax", nil},
+		{"this:key:has:a:long:common:prefix:1:more", nil},
+	}
+
+	tr := art.NewAlphaSortedTree[string, int]()
+	for i, k := range keys {
+		tr.Insert(k, i)
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("prefix-%s", tt.prefix), func(t *testing.T) {
+			var got []string
+			for k, _ := range tr.Prefix(tt.prefix) {
+				got = append(got, k)
+			}
+
+			if !slices.Equal(tt.expected, got) {
+				t.Fatalf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestAlphaPrefixBackward(t *testing.T) {
+	tr := art.NewAlphaSortedTree[string, int]()
+	keys := []string{"api.foo.bar", "api.foo.baz", "api.foe.fum", "abc.123.456", "api.foo", "api"}
+
+	for i, k := range keys {
+		tr.Insert(k, i)
+	}
+
+	var got []string
+	for k, _ := range tr.PrefixBackward("api.") {
+		got = append(got, k)
+	}
+
+	if expected := []string{"api.foo.baz", "api.foo.bar", "api.foo", "api.foe.fum"}; !slices.Equal(expected, got) {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+}
+
+func TestAlphaPrefixWords(t *testing.T) {
+	words := loadTestFile("testdata/words.txt")
+	tr := art.NewAlphaSortedTree[[]byte, int]()
+
+	var keys []string
+	for i, w := range words {
+		tr.Insert(w, i)
+		keys = append(keys, string(w))
+	}
+
+	slices.Sort(keys)
+	keys = slices.Compact(keys)
+
+	for _, prefix := range []string{"a", "ab", "elect", "electro", "interc", "pseudo", "unde", "zz", "Q"} {
+		var expected []string
+		for _, k := range keys {
+			if strings.HasPrefix(k, prefix) {
+				expected = append(expected, k)
+			}
+		}
+
+		var got []string
+		for k, _ := range tr.Prefix([]byte(prefix)) {
+			got = append(got, string(k))
+		}
+
+		if !slices.Equal(expected, got) {
+			t.Fatalf("prefix %q: expected %d keys, got %d", prefix, len(expected), len(got))
+		}
+	}
+}
+
+func TestAlphaBinaryKeys(t *testing.T) {
+	tr := art.NewAlphaSortedTree[[]byte, int](art.WithBinaryKeys())
+	keys := []string{"", "\x00", "\x00\x00", "\x00\x01", "a", "a\x00", "a\x00b", "a\x01", "ab", "\xff\x00"}
+
+	for i, k := range keys {
+		tr.Insert([]byte(k), i)
+	}
+
+	if tr.Size() != len(keys) {
+		t.Fatalf("expected size %d, got %d", len(keys), tr.Size())
+	}
+
+	for i, k := range keys {
+		if v, ok := tr.Search([]byte(k)); !ok || v != i {
+			t.Fatalf("expected %q to be found with %d, got %d", k, i, v)
+		}
+	}
+
+	var got []string
+	for k, _ := range tr.All() {
+		got = append(got, string(k))
+	}
+
+	if !slices.Equal(keys, got) {
+		t.Fatalf("expected %q, got %q", keys, got)
+	}
+
+	if k, _, _ := tr.Minimum(); string(k) != "" {
+		t.Fatalf("expected minimum %q, got %q", "", k)
+	}
+	if k, _, _ := tr.Maximum(); string(k) != "\xff\x00" {
+		t.Fatalf("expected maximum %q, got %q", "\xff\x00", k)
+	}
+
+	got = got[:0]
+	for k, _ := range tr.Prefix([]byte("a\x00")) {
+		got = append(got, string(k))
+	}
+
+	if expected := []string{"a\x00", "a\x00b"}; !slices.Equal(expected, got) {
+		t.Fatalf("expected %q, got %q", expected, got)
+	}
+
+	got = got[:0]
+	for k, _ := range tr.Range([]byte("\x00\x00"), []byte("a\x00b")) {
+		got = append(got, string(k))
+	}
+
+	if expected := keys[2:7]; !slices.Equal(expected, got) {
+		t.Fatalf("expected %q, got %q", expected, got)
+	}
+
+	tr.Delete([]byte("a\x00"))
+	if _, ok := tr.Search([]byte("a\x00")); ok {
+		t.Fatal("expected a\\x00 to be deleted")
+	}
+	if _, ok := tr.Search([]byte("a\x00b")); !ok {
+		t.Fatal("expected a\\x00b to survive the deletion of a\\x00")
+	}
+}
+
+func TestAlphaBinaryKeysRandom(t *testing.T) {
+	r := rand.New(rand.NewPCG(7, 8))
+	tr := art.NewAlphaSortedTree[string, int](art.WithBinaryKeys())
+
+	var keys []string
+	for i := range 10_000 {
+		// small alphabet to get plenty of 0x00 and shared prefixes
+		b := make([]byte, r.IntN(6))
+		for j := range b {
+			b[j] = byte(r.IntN(3))
+		}
+		tr.Insert(string(b), i)
+		keys = append(keys, string(b))
+	}
+
+	slices.Sort(keys)
+	keys = slices.Compact(keys)
+
+	var got []string
+	for k, _ := range tr.All() {
+		got = append(got, k)
+	}
+
+	if !slices.Equal(keys, got) {
+		t.Fatalf("expected %d sorted keys, got %d", len(keys), len(got))
+	}
+}
@@ -0,0 +1,279 @@
+package art
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// NewBigIntTree returns a tree ordering integers of any size.
+func NewBigIntTree[V any](opts ...Option) Tree[*big.Int, V] {
+	return NewCompoundTree[*big.Int, V](BigIntKey{}, opts...)
+}
+
+// NewDecimalTree returns a tree ordering decimal strings by value.
+func NewDecimalTree[K chars, V any](opts ...Option) Tree[K, V] {
+	return NewCompoundTree[K, V](DecimalKey[K]{}, opts...)
+}
+
+// NewRatTree returns a tree ordering rationals with a finite decimal
+// expansion.
+func NewRatTree[V any](opts ...Option) Tree[*big.Rat, V] {
+	return NewCompoundTree[*big.Rat, V](RatKey{}, opts...)
+}
+
+// sign bytes of the numbers
+const (
+	negativeNumber = 0x7f
+	zeroNumber     = 0x80
+	positiveNumber = 0x81
+)
+
+// BigIntKey orders integers of any size. The magnitude is prefixed by its
+// length so that longer numbers sort after shorter ones, and negative
+// numbers are inverted. A nil key is treated as 0.
+type BigIntKey struct{}
+
+func (bik BigIntKey) Transform(k *big.Int) ([]byte, []byte) {
+	if k == nil || k.Sign() == 0 {
+		return []byte{zeroNumber}, []byte{zeroNumber}
+	}
+
+	mag := k.Bytes()
+	b := make([]byte, 0, 5+len(mag))
+	b = append(b, positiveNumber)
+	b = binary.BigEndian.AppendUint32(b, uint32(len(mag)))
+	b = append(b, mag...)
+
+	if k.Sign() < 0 {
+		b[0] = negativeNumber
+		invert(b[1:], b[1:])
+	}
+	return b, b
+}
+func (bik BigIntKey) Restore(b []byte) *big.Int {
+	k := new(big.Int)
+	if b[0] == zeroNumber {
+		return k
+	}
+
+	mag := b[5:]
+	if b[0] == negativeNumber {
+		mag = invert(make([]byte, len(mag)), mag)
+		return k.Neg(k.SetBytes(mag))
+	}
+	return k.SetBytes(mag)
+}
+
+var _ BinaryComparableKey[*big.Int] = BigIntKey{}
+
+// decimal is a finite decimal number: ±0.digits × 10^exp, with digits
+// holding ASCII digits without leading or trailing zeros. The zero decimal
+// has no digits.
+type decimal struct {
+	neg    bool
+	exp    int32
+	digits []byte
+}
+
+// parseDecimal parses numbers such as "12", "-0.50" or "1.5e-3".
+func parseDecimal(s string) (decimal, error) {
+	var d decimal
+
+	num := s
+	if i := strings.IndexAny(num, "eE"); i != -1 {
+		e, err := strconv.ParseInt(num[i+1:], 10, 32)
+		if err != nil {
+			return d, fmt.Errorf("art: invalid decimal %q", s)
+		}
+		d.exp = int32(e)
+		num = num[:i]
+	}
+
+	if len(num) > 0 && (num[0] == '-' || num[0] == '+') {
+		d.neg = num[0] == '-'
+		num = num[1:]
+	}
+
+	intPart, fracPart, _ := strings.Cut(num, ".")
+	if len(intPart)+len(fracPart) == 0 {
+		return d, fmt.Errorf("art: invalid decimal %q", s)
+	}
+
+	d.digits = make([]byte, 0, len(intPart)+len(fracPart))
+	d.digits = append(d.digits, intPart...)
+	d.digits = append(d.digits, fracPart...)
+	d.exp += int32(len(intPart))
+
+	for _, c := range d.digits {
+		if c < '0' || c > '9' {
+			return d, fmt.Errorf("art: invalid decimal %q", s)
+		}
+	}
+
+	d.normalize()
+	return d, nil
+}
+
+// normalize strips the leading and trailing zeros.
+func (d *decimal) normalize() {
+	lead := 0
+	for lead < len(d.digits) && d.digits[lead] == '0' {
+		lead++
+	}
+	d.digits = d.digits[lead:]
+	d.exp -= int32(lead)
+
+	d.digits = []byte(strings.TrimRight(string(d.digits), "0"))
+	if len(d.digits) == 0 {
+		*d = decimal{}
+	}
+}
+
+// String formats the decimal in plain notation, or in scientific notation
+// when the exponent is far from the digits.
+func (d decimal) String() string {
+	if len(d.digits) == 0 {
+		return "0"
+	}
+
+	var sb strings.Builder
+	if d.neg {
+		sb.WriteByte('-')
+	}
+
+	n := int(d.exp)
+	switch {
+	case n > len(d.digits)+maxPlainZeros || n < -maxPlainZeros:
+		sb.WriteByte(d.digits[0])
+		if len(d.digits) > 1 {
+			sb.WriteByte('.')
+			sb.Write(d.digits[1:])
+		}
+		sb.WriteByte('e')
+		sb.WriteString(strconv.Itoa(n - 1))
+	case n <= 0:
+		sb.WriteString("0.")
+		sb.WriteString(strings.Repeat("0", -n))
+		sb.Write(d.digits)
+	case n >= len(d.digits):
+		sb.Write(d.digits)
+		sb.WriteString(strings.Repeat("0", n-len(d.digits)))
+	default:
+		sb.Write(d.digits[:n])
+		sb.WriteByte('.')
+		sb.Write(d.digits[n:])
+	}
+	return sb.String()
+}
+
+// maxPlainZeros is the number of zeros added before switching to the
+// scientific notation.
+const maxPlainZeros = 32
+
+// encode stores the sign, then the exponent and the digits followed by a 0x00
+// end. Both are inverted for negative numbers.
+func (d decimal) encode() []byte {
+	if len(d.digits) == 0 {
+		return []byte{zeroNumber}
+	}
+
+	b := make([]byte, 0, 6+len(d.digits))
+	b = append(b, positiveNumber)
+	b = binary.BigEndian.AppendUint32(b, uint32(d.exp)^0x80000000)
+	b = append(b, d.digits...)
+	b = append(b, 0x00)
+
+	if d.neg {
+		b[0] = negativeNumber
+		invert(b[1:], b[1:])
+	}
+	return b
+}
+
+func decodeDecimal(b []byte) decimal {
+	if b[0] == zeroNumber {
+		return decimal{}
+	}
+
+	d := decimal{neg: b[0] == negativeNumber}
+	b = b[1:]
+	if d.neg {
+		b = invert(make([]byte, len(b)), b)
+	}
+
+	d.exp = int32(binary.BigEndian.Uint32(b) ^ 0x80000000)
+	d.digits = b[4 : len(b)-1]
+	return d
+}
+
+// DecimalKey orders decimal strings such as "12", "-0.50" or "1.5e-3" by
+// value, whatever their size. The keys are restored in a canonical form,
+// e.g. "0.5" for "+.50", and keys of the same value are the same key.
+// Transform panics on an invalid decimal.
+type DecimalKey[K chars] struct{}
+
+func (dk DecimalKey[K]) Transform(k K) ([]byte, []byte) {
+	d, err := parseDecimal(string(k))
+	if err != nil {
+		panic(err)
+	}
+
+	b := d.encode()
+	return b, b
+}
+func (dk DecimalKey[K]) Restore(b []byte) K {
+	return K(decodeDecimal(b).String())
+}
+
+var _ BinaryComparableKey[string] = DecimalKey[string]{}
+
+// RatKey orders rationals with the DecimalKey encoding. Transform panics on
+// rationals without a finite decimal expansion, such as 1/3. A nil key is
+// treated as 0.
+type RatKey struct{}
+
+func (rk RatKey) Transform(k *big.Rat) ([]byte, []byte) {
+	if k == nil {
+		return []byte{zeroNumber}, []byte{zeroNumber}
+	}
+
+	// find the power of ten making the denominator divide the numerator
+	denom := new(big.Int).Set(k.Denom())
+	scale := int32(0)
+	ten, rem := big.NewInt(10), new(big.Int)
+	for _, f := range []int64{2, 5} {
+		n := 0
+		for rem.Mod(denom, big.NewInt(f)).Sign() == 0 {
+			denom.Quo(denom, big.NewInt(f))
+			n++
+		}
+		scale = max(scale, int32(n))
+	}
+	if denom.Cmp(big.NewInt(1)) != 0 {
+		panic(fmt.Sprintf("art: %s has no finite decimal expansion", k))
+	}
+
+	num := new(big.Int).Exp(ten, big.NewInt(int64(scale)), nil)
+	num.Mul(num, k.Num())
+	num.Quo(num, k.Denom())
+
+	digits := num.Abs(num).String()
+	d := decimal{
+		neg:    k.Sign() < 0,
+		exp:    int32(len(digits)) - scale,
+		digits: []byte(digits),
+	}
+	d.normalize()
+
+	b := d.encode()
+	return b, b
+}
+func (rk RatKey) Restore(b []byte) *big.Rat {
+	r, _ := new(big.Rat).SetString(decodeDecimal(b).String())
+	return r
+}
+
+var _ BinaryComparableKey[*big.Rat] = RatKey{}
@@ -0,0 +1,105 @@
+package art_test
+
+import (
+	"math/big"
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	"github.com/Clement-Jean/go-art"
+)
+
+func TestBigIntTree(t *testing.T) {
+	r := rand.New(rand.NewPCG(9, 10))
+	tr := art.NewBigIntTree[int]()
+
+	var keys []*big.Int
+	for i := range 2_000 {
+		// up to 25 bytes to mix numbers of many lengths
+		mag := make([]byte, r.IntN(26))
+		for j := range mag {
+			mag[j] = byte(r.Uint32())
+		}
+		k := new(big.Int).SetBytes(mag)
+		if r.IntN(2) == 0 {
+			k.Neg(k)
+		}
+		tr.Insert(k, i)
+		keys = append(keys, k)
+	}
+
+	slices.SortFunc(keys, func(a, b *big.Int) int { return a.Cmp(b) })
+	keys = slices.CompactFunc(keys, func(a, b *big.Int) bool { return a.Cmp(b) == 0 })
+
+	var got []*big.Int
+	for k, _ := range tr.All() {
+		got = append(got, k)
+	}
+
+	if !slices.EqualFunc(keys, got, func(a, b *big.Int) bool { return a.Cmp(b) == 0 }) {
+		t.Fatalf("expected %d sorted keys, got %d", len(keys), len(got))
+	}
+
+	amount, _ := new(big.Int).SetString("170141183460469231731687303715884105727", 10) // 2^127-1
+	tr.Insert(amount, -1)
+	if v, ok := tr.Search(new(big.Int).Set(amount)); !ok || v != -1 {
+		t.Fatalf("expected %s to be found", amount)
+	}
+	if k, _, _ := tr.Maximum(); k.Cmp(amount) < 0 {
+		t.Fatalf("expected maximum of at least %s, got %s", amount, k)
+	}
+}
+
+func TestDecimalTree(t *testing.T) {
+	tr := art.NewDecimalTree[string, int]()
+	keys := []string{
+		"1", "-1", "0", "-0.0", "10", "9.99", "100e-2", "0.001", "-0.001",
+		"-12345678901234567890.5", "12345678901234567890.5", "1.5e40", "-2e-40", "+.50",
+	}
+
+	for i, k := range keys {
+		tr.Insert(k, i)
+	}
+
+	var got []string
+	for k, _ := range tr.All() {
+		got = append(got, k)
+	}
+
+	expected := []string{
+		"-12345678901234567890.5", "-1", "-0.001", "-2e-40", "0", "0.001", "0.5",
+		"1", "9.99", "10", "12345678901234567890.5", "1.5e40",
+	}
+	if !slices.Equal(expected, got) {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+
+	if v, ok := tr.Search("1.000"); !ok || v != 6 {
+		t.Fatalf("expected 1.000 to be the same key as 100e-2, got %d", v)
+	}
+}
+
+func TestRatTree(t *testing.T) {
+	tr := art.NewRatTree[int]()
+
+	for i, s := range []string{"1/4", "-3/2", "7", "1/8", "-1/1024"} {
+		r, _ := new(big.Rat).SetString(s)
+		tr.Insert(r, i)
+	}
+
+	var got []string
+	for k, _ := range tr.All() {
+		got = append(got, k.RatString())
+	}
+
+	if expected := []string{"-3/2", "-1/1024", "1/8", "1/4", "7"}; !slices.Equal(expected, got) {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected a panic for 1/3")
+		}
+	}()
+	tr.Insert(big.NewRat(1, 3), 0)
+}
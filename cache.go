@@ -0,0 +1,383 @@
+package art
+
+import (
+	"fmt"
+	"iter"
+	"unsafe"
+)
+
+// CacheTree is a Tree bounded by a number of entries or a number of bytes,
+// evicting the least recently used entries, or the least frequently used
+// ones with WithLFU. Only Insert and Search count as uses: the iterations
+// don't change the eviction order.
+type CacheTree[K nodeKey, V any] interface {
+	Tree[K, V]
+
+	// OnEvict sets the function called with each entry evicted by Insert.
+	OnEvict(func(K, V))
+
+	// CacheStats returns the hits, misses and evictions so far.
+	CacheStats() CacheStats
+}
+
+// CacheStats are the statistics of a CacheTree.
+type CacheStats struct {
+	Hits, Misses, Evictions int
+
+	// Bytes is the size of the entries counted against WithMaxBytes.
+	Bytes int
+}
+
+// Sizer returns the number of bytes of a value.
+type Sizer[V any] func(V) int
+
+// NewCacheTree returns a CacheTree storing its entries in the given empty
+// tree, e.g. NewAlphaSortedTree[string, *CacheEntry[string, int]](), and
+// evicting entries when it exceeds the bounds given by WithMaxEntries and
+// WithMaxBytes. It is unbounded without them.
+func NewCacheTree[K nodeKey, V any](inner Tree[K, *CacheEntry[K, V]], opts ...Option) CacheTree[K, V] {
+	if inner.Size() != 0 {
+		panic("art: NewCacheTree needs an empty tree")
+	}
+
+	o := newTreeOptions(treeOptions{}, opts)
+	sizer, ok := o.sizer.(Sizer[V])
+	if o.sizer != nil && !ok {
+		panic(fmt.Sprintf("art: WithMaxBytes sizer %T doesn't size the values", o.sizer))
+	}
+
+	t := &cacheTree[K, V]{
+		inner:      inner,
+		maxEntries: o.maxEntries,
+		maxBytes:   o.maxBytes,
+		sizer:      sizer,
+		lfu:        o.lfu,
+	}
+	t.recent.init()
+	t.buckets.next, t.buckets.prev = &t.buckets, &t.buckets
+
+	return t
+}
+
+// WithMaxEntries bounds the number of entries of a CacheTree.
+func WithMaxEntries(n int) Option {
+	return func(o *treeOptions) {
+		o.maxEntries = n
+	}
+}
+
+// WithMaxBytes bounds the size of the entries of a CacheTree, which is the
+// length of their key in bytes plus the size of their value given by sizer.
+// The keys which aren't text count the length of their encoding in the tree.
+// A nil sizer only counts the keys.
+func WithMaxBytes[V any](n int, sizer Sizer[V]) Option {
+	return func(o *treeOptions) {
+		o.maxBytes = n
+		if sizer != nil {
+			o.sizer = sizer
+		}
+	}
+}
+
+// WithLFU makes a CacheTree evict the least frequently used entries, the
+// least recently used first among the entries used as often.
+func WithLFU() Option {
+	return func(o *treeOptions) {
+		o.lfu = true
+	}
+}
+
+// CacheEntry is the value stored by a CacheTree in its inner tree. It is
+// linked in the list of recently used entries, or in the list of its
+// frequency bucket with LFU.
+type CacheEntry[K nodeKey, V any] struct {
+	key        K
+	value      V
+	size       int
+	prev, next *CacheEntry[K, V]
+	bucket     *freqBucket[K, V]
+}
+
+// entryList is a circular list of entries, from the most to the least
+// recently used.
+type entryList[K nodeKey, V any] struct {
+	root CacheEntry[K, V]
+}
+
+func (l *entryList[K, V]) init() {
+	l.root.next, l.root.prev = &l.root, &l.root
+}
+
+func (l *entryList[K, V]) empty() bool { return l.root.next == &l.root }
+
+// backExcept returns the least recently used entry other than skip, or nil.
+func (l *entryList[K, V]) backExcept(skip *CacheEntry[K, V]) *CacheEntry[K, V] {
+	e := l.root.prev
+	if e == skip {
+		e = e.prev
+	}
+	if e == &l.root {
+		return nil
+	}
+	return e
+}
+
+func (l *entryList[K, V]) pushFront(e *CacheEntry[K, V]) {
+	e.prev, e.next = &l.root, l.root.next
+	e.prev.next, e.next.prev = e, e
+}
+
+func (l *entryList[K, V]) remove(e *CacheEntry[K, V]) {
+	e.prev.next, e.next.prev = e.next, e.prev
+	e.prev, e.next = nil, nil
+}
+
+// freqBucket holds the entries used freq times. The buckets are linked in a
+// circular list in increasing order of frequency, so that evicting and
+// counting a use are constant time.
+type freqBucket[K nodeKey, V any] struct {
+	freq       uint64
+	entries    entryList[K, V]
+	prev, next *freqBucket[K, V]
+}
+
+type cacheTree[K nodeKey, V any] struct {
+	inner Tree[K, *CacheEntry[K, V]]
+
+	maxEntries, maxBytes int
+	sizer                Sizer[V]
+	lfu                  bool
+
+	recent  entryList[K, V]
+	buckets freqBucket[K, V] // sentinel of the buckets
+
+	stats   CacheStats
+	onEvict func(K, V)
+}
+
+// entrySize returns the size of an entry counted against maxBytes.
+func (t *cacheTree[K, V]) entrySize(key K, val V) int {
+	if t.maxBytes == 0 {
+		return 0
+	}
+
+	var n int
+	switch k := any(key).(type) {
+	case string:
+		n = len(k)
+	case []byte:
+		n = len(k)
+	case []rune:
+		n = len(string(k))
+	default:
+		if enc, ok := t.inner.(interface{ encodeKey(K) []byte }); ok {
+			n = len(enc.encodeKey(key))
+		} else {
+			n = int(unsafe.Sizeof(key))
+		}
+	}
+	if t.sizer != nil {
+		n += t.sizer(val)
+	}
+	return n
+}
+
+// Insert inserts an entry and then evicts the other entries until the tree is
+// within its bounds. An entry which doesn't fit on its own is evicted right
+// away, leaving the other entries.
+func (t *cacheTree[K, V]) Insert(key K, val V) {
+	size := t.entrySize(key, val)
+
+	e, ok := t.inner.Search(key)
+	if ok {
+		t.stats.Bytes += size - e.size
+		e.value, e.size = val, size
+		t.touch(e)
+	} else {
+		e = &CacheEntry[K, V]{key: cloneKey(key), value: val, size: size}
+		t.inner.Insert(e.key, e)
+		t.stats.Bytes += size
+		t.link(e)
+	}
+
+	if t.maxBytes > 0 && size > t.maxBytes {
+		t.evict(e)
+		return
+	}
+
+	for t.overflows() {
+		t.evict(t.victim(e))
+	}
+}
+
+func (t *cacheTree[K, V]) overflows() bool {
+	return (t.maxEntries > 0 && t.inner.Size() > t.maxEntries) ||
+		(t.maxBytes > 0 && t.stats.Bytes > t.maxBytes)
+}
+
+// link adds a new entry as the most recently used, used once.
+func (t *cacheTree[K, V]) link(e *CacheEntry[K, V]) {
+	if !t.lfu {
+		t.recent.pushFront(e)
+		return
+	}
+
+	b := t.buckets.next
+	if b == &t.buckets || b.freq != 1 {
+		b = t.insertBucket(&t.buckets, 1)
+	}
+	e.bucket = b
+	b.entries.pushFront(e)
+}
+
+// unlink removes an entry from the eviction order.
+func (t *cacheTree[K, V]) unlink(e *CacheEntry[K, V]) {
+	if !t.lfu {
+		t.recent.remove(e)
+		return
+	}
+
+	b := e.bucket
+	b.entries.remove(e)
+	e.bucket = nil
+	if b.entries.empty() {
+		b.prev.next, b.next.prev = b.next, b.prev
+	}
+}
+
+// touch counts a use of an entry.
+func (t *cacheTree[K, V]) touch(e *CacheEntry[K, V]) {
+	if !t.lfu {
+		t.recent.remove(e)
+		t.recent.pushFront(e)
+		return
+	}
+
+	b := e.bucket
+	next := b.next
+	if next == &t.buckets || next.freq != b.freq+1 {
+		next = t.insertBucket(b, b.freq+1)
+	}
+	t.unlink(e)
+	e.bucket = next
+	next.entries.pushFront(e)
+}
+
+// insertBucket inserts an empty bucket after prev.
+func (t *cacheTree[K, V]) insertBucket(prev *freqBucket[K, V], freq uint64) *freqBucket[K, V] {
+	b := &freqBucket[K, V]{freq: freq, prev: prev, next: prev.next}
+	b.entries.init()
+	prev.next.prev = b
+	prev.next = b
+	return b
+}
+
+// victim returns the least recently used entry other than skip, of the least
+// used ones with LFU, or nil.
+func (t *cacheTree[K, V]) victim(skip *CacheEntry[K, V]) *CacheEntry[K, V] {
+	if !t.lfu {
+		return t.recent.backExcept(skip)
+	}
+
+	for b := t.buckets.next; b != &t.buckets; b = b.next {
+		if e := b.entries.backExcept(skip); e != nil {
+			return e
+		}
+	}
+	return nil
+}
+
+func (t *cacheTree[K, V]) evict(e *CacheEntry[K, V]) {
+	t.remove(e)
+	t.stats.Evictions++
+	if t.onEvict != nil {
+		t.onEvict(e.key, e.value)
+	}
+}
+
+func (t *cacheTree[K, V]) remove(e *CacheEntry[K, V]) {
+	t.unlink(e)
+	t.inner.Delete(e.key)
+	t.stats.Bytes -= e.size
+}
+
+func (t *cacheTree[K, V]) Search(key K) (V, bool) {
+	e, ok := t.inner.Search(key)
+	if !ok {
+		t.stats.Misses++
+		var zero V
+		return zero, false
+	}
+
+	t.stats.Hits++
+	t.touch(e)
+	return e.value, true
+}
+
+func (t *cacheTree[K, V]) Delete(key K) bool {
+	e, ok := t.inner.Search(key)
+	if !ok {
+		return false
+	}
+
+	t.remove(e)
+	return true
+}
+
+func (t *cacheTree[K, V]) OnEvict(fn func(K, V)) { t.onEvict = fn }
+
+func (t *cacheTree[K, V]) CacheStats() CacheStats { return t.stats }
+
+func (t *cacheTree[K, V]) Minimum() (K, V, bool) {
+	k, e, ok := t.inner.Minimum()
+	if !ok {
+		var zero V
+		return k, zero, false
+	}
+	return k, e.value, true
+}
+
+func (t *cacheTree[K, V]) Maximum() (K, V, bool) {
+	k, e, ok := t.inner.Maximum()
+	if !ok {
+		var zero V
+		return k, zero, false
+	}
+	return k, e.value, true
+}
+
+func (t *cacheTree[K, V]) All() iter.Seq2[K, V] { return cached(t.inner.All()) }
+
+func (t *cacheTree[K, V]) Backward() iter.Seq2[K, V] { return cached(t.inner.Backward()) }
+
+func (t *cacheTree[K, V]) Prefix(p K) iter.Seq2[K, V] { return cached(t.inner.Prefix(p)) }
+
+func (t *cacheTree[K, V]) PrefixBackward(p K) iter.Seq2[K, V] {
+	return cached(t.inner.PrefixBackward(p))
+}
+
+func (t *cacheTree[K, V]) TopK(k uint) iter.Seq2[K, V] { return cached(t.inner.TopK(k)) }
+
+func (t *cacheTree[K, V]) BottomK(k uint) iter.Seq2[K, V] { return cached(t.inner.BottomK(k)) }
+
+func (t *cacheTree[K, V]) Range(start, end K) iter.Seq2[K, V] {
+	return cached(t.inner.Range(start, end))
+}
+
+func (t *cacheTree[K, V]) RangeWith(opts RangeOptions[K]) iter.Seq2[K, V] {
+	return cached(t.inner.RangeWith(opts))
+}
+
+func (t *cacheTree[K, V]) Size() int { return t.inner.Size() }
+
+func (t *cacheTree[K, V]) Stats() Stats { return t.inner.Stats() }
+
+func cached[K nodeKey, V any](seq iter.Seq2[K, *CacheEntry[K, V]]) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for k, e := range seq {
+			if !yield(k, e.value) {
+				return
+			}
+		}
+	}
+}
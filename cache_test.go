@@ -0,0 +1,195 @@
+package art_test
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/Clement-Jean/go-art"
+)
+
+func TestCacheLRU(t *testing.T) {
+	tr := art.NewCacheTree(art.NewAlphaSortedTree[string, *art.CacheEntry[string, int]](), art.WithMaxEntries(3))
+
+	var evicted []string
+	tr.OnEvict(func(k string, v int) { evicted = append(evicted, k) })
+
+	tr.Insert("a", 1)
+	tr.Insert("b", 2)
+	tr.Insert("c", 3)
+	tr.Search("a")
+	tr.Insert("d", 4)
+	tr.Insert("c", 30)
+	tr.Insert("e", 5)
+
+	if expected := []string{"b", "a"}; !slices.Equal(expected, evicted) {
+		t.Fatalf("expected %v to be evicted, got %v", expected, evicted)
+	}
+
+	var got []string
+	for k := range tr.All() {
+		got = append(got, k)
+	}
+	if expected := []string{"c", "d", "e"}; !slices.Equal(expected, got) {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+
+	tr.Search("b")
+	if s := tr.CacheStats(); s.Hits != 1 || s.Misses != 1 || s.Evictions != 2 {
+		t.Fatalf("expected 1 hit, 1 miss and 2 evictions, got %+v", s)
+	}
+}
+
+func TestCacheLFU(t *testing.T) {
+	tr := art.NewCacheTree(art.NewAlphaSortedTree[string, *art.CacheEntry[string, int]](), art.WithMaxEntries(3), art.WithLFU())
+
+	var evicted []string
+	tr.OnEvict(func(k string, v int) { evicted = append(evicted, k) })
+
+	tr.Insert("a", 1)
+	tr.Insert("b", 2)
+	tr.Insert("c", 3)
+	for range 3 {
+		tr.Search("a")
+	}
+	tr.Search("b")
+	tr.Search("c")
+	tr.Search("c")
+
+	tr.Insert("d", 4) // evicts b, the least used before d
+	tr.Insert("e", 5)
+	tr.Search("e")
+	tr.Insert("f", 6)
+
+	if expected := []string{"b", "d", "e"}; !slices.Equal(expected, evicted) {
+		t.Fatalf("expected %v to be evicted, got %v", expected, evicted)
+	}
+
+	var got []string
+	for k := range tr.All() {
+		got = append(got, k)
+	}
+	if expected := []string{"a", "c", "f"}; !slices.Equal(expected, got) {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+}
+
+func TestCacheMaxBytes(t *testing.T) {
+	tr := art.NewCacheTree(
+		art.NewAlphaSortedTree[[]byte, *art.CacheEntry[[]byte, []byte]](),
+		art.WithMaxBytes(100, func(v []byte) int { return len(v) }),
+	)
+
+	for i := range 10 {
+		tr.Insert([]byte(fmt.Sprintf("key%d", i)), make([]byte, 16))
+	}
+
+	// every entry takes 4+16 bytes
+	if tr.Size() != 5 {
+		t.Fatalf("expected 5 entries, got %d", tr.Size())
+	}
+	if s := tr.CacheStats(); s.Bytes != 100 || s.Evictions != 5 {
+		t.Fatalf("expected 100 bytes and 5 evictions, got %+v", s)
+	}
+
+	tr.Insert([]byte("key9"), make([]byte, 56))
+	if tr.Size() != 3 {
+		t.Fatalf("expected 3 entries, got %d", tr.Size())
+	}
+
+	tr.Insert([]byte("huge"), make([]byte, 200))
+	if tr.Size() != 3 {
+		t.Fatalf("expected only the oversized entry to be evicted, got %d entries", tr.Size())
+	}
+	if _, ok := tr.Search([]byte("huge")); ok {
+		t.Fatal("expected the oversized entry to be evicted")
+	}
+
+	tr.Insert([]byte("key9"), make([]byte, 200))
+	if tr.Delete([]byte("key9")) {
+		t.Fatal("expected key9 to be evicted when it outgrows the cache")
+	}
+	if s := tr.CacheStats(); s.Bytes != 40 || tr.Size() != 2 {
+		t.Fatalf("expected 2 entries of 40 bytes, got %d and %+v", tr.Size(), s)
+	}
+}
+
+func TestCacheOversizedEntry(t *testing.T) {
+	tr := art.NewCacheTree(art.NewAlphaSortedTree[string, *art.CacheEntry[string, int]](), art.WithMaxBytes[int](10, nil))
+
+	var evicted []string
+	tr.OnEvict(func(k string, v int) { evicted = append(evicted, k) })
+
+	for _, k := range []string{"a", "b", "c"} {
+		tr.Insert(k, 0)
+	}
+	tr.Insert("a key longer than 10 bytes", 0)
+
+	var got []string
+	for k := range tr.All() {
+		got = append(got, k)
+	}
+	if expected := []string{"a", "b", "c"}; !slices.Equal(expected, got) {
+		t.Fatalf("expected the other entries %v to survive, got %v", expected, got)
+	}
+	if expected := []string{"a key longer than 10 bytes"}; !slices.Equal(expected, evicted) {
+		t.Fatalf("expected %v to be evicted, got %v", expected, evicted)
+	}
+	if s := tr.CacheStats(); s.Evictions != 1 || s.Bytes != 3 {
+		t.Fatalf("expected 1 eviction and 3 bytes, got %+v", s)
+	}
+}
+
+func TestCachePrefix(t *testing.T) {
+	tr := art.NewCacheTree(art.NewAlphaSortedTree[string, *art.CacheEntry[string, int]](), art.WithMaxEntries(100), art.WithLFU())
+
+	for i := range 200 {
+		tr.Insert(fmt.Sprintf("route:%03d", i), i)
+	}
+
+	var got []string
+	for k := range tr.Prefix("route:19") {
+		got = append(got, k)
+	}
+
+	expected := []string{}
+	for i := 190; i < 200; i++ {
+		expected = append(expected, fmt.Sprintf("route:%03d", i))
+	}
+	if !slices.Equal(expected, got) {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+}
+
+func TestCacheNumericKeys(t *testing.T) {
+	tr := art.NewCacheTree(
+		art.NewUnsignedBinaryTree[uint32, *art.CacheEntry[uint32, string]](),
+		art.WithMaxBytes(30, func(v string) int { return len(v) }),
+	)
+
+	// every entry takes 4+6 bytes
+	for i := range uint32(5) {
+		tr.Insert(i, "value0")
+	}
+
+	var got []uint32
+	for k := range tr.All() {
+		got = append(got, k)
+	}
+	if expected := []uint32{2, 3, 4}; !slices.Equal(expected, got) {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+}
+
+func TestCacheSizerType(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected a panic for a sizer of another value type")
+		}
+	}()
+
+	art.NewCacheTree(
+		art.NewAlphaSortedTree[string, *art.CacheEntry[string, int]](),
+		art.WithMaxBytes(10, func(v string) int { return len(v) }),
+	)
+}
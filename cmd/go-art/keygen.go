@@ -0,0 +1,228 @@
+package main
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"maps"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+//go:embed key.tmpl
+var keyTmpl embed.FS
+
+type Key struct {
+	Name   string
+	Fields []KeyField
+}
+
+type KeyField struct {
+	Name, Encoder string
+
+	// Base is the builtin type the encoder works on and Type the type of the
+	// field, converted from and to Base when they differ.
+	Base, Type string
+	Convert    bool
+
+	pos int
+}
+
+type keyFile struct {
+	Types   string
+	Package string
+	Imports []string
+	Keys    []Key
+}
+
+// generateKeys writes the BinaryComparableKey of the given structs of the
+// package in the current directory, derived from their art tags like
+// art.KeyOf does.
+func generateKeys(typeNames []string, output string) error {
+	pkg, err := loadPackage()
+	if err != nil {
+		return err
+	}
+
+	out := keyFile{Types: strings.Join(typeNames, ","), Package: pkg.Name()}
+	imports := map[string]bool{}
+	qualifier := func(p *types.Package) string {
+		if p == pkg {
+			return ""
+		}
+		imports[p.Path()] = true
+		return p.Name()
+	}
+
+	for _, typ := range typeNames {
+		obj, ok := pkg.Scope().Lookup(typ).(*types.TypeName)
+		if !ok {
+			return fmt.Errorf("struct %s not found", typ)
+		}
+		st, ok := obj.Type().Underlying().(*types.Struct)
+		if !ok {
+			return fmt.Errorf("%s isn't a struct", typ)
+		}
+
+		key, err := structKey(typ, st, qualifier)
+		if err != nil {
+			return err
+		}
+		out.Keys = append(out.Keys, key)
+	}
+	delete(imports, artPath)
+	out.Imports = slices.Sorted(maps.Keys(imports))
+
+	tmpl, err := template.ParseFS(keyTmpl, "key.tmpl")
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, out); err != nil {
+		return err
+	}
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return err
+	}
+
+	if output == "" {
+		output = strings.ToLower(typeNames[0]) + "_key.go"
+	}
+	return os.WriteFile(output, src, 0644)
+}
+
+const artPath = "github.com/Clement-Jean/go-art"
+
+// loadPackage type-checks the package in the current directory. The type
+// errors are ignored since the package may use the keys not generated yet.
+func loadPackage() (*types.Package, error) {
+	fset := token.NewFileSet()
+	names, err := filepath.Glob("*.go")
+	if err != nil {
+		return nil, err
+	}
+
+	var files []*ast.File
+	for _, name := range names {
+		if strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+
+		f, err := parser.ParseFile(fset, name, nil, 0)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, f)
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no Go files in the current directory")
+	}
+
+	conf := types.Config{
+		Importer: importer.ForCompiler(fset, "source", nil),
+		Error:    func(error) {},
+	}
+	pkg, _ := conf.Check(files[0].Name.Name, fset, files, nil)
+	return pkg, nil
+}
+
+func structKey(typ string, st *types.Struct, qualifier types.Qualifier) (Key, error) {
+	key := Key{Name: typ}
+
+	for i := range st.NumFields() {
+		field := st.Field(i)
+		tag, ok := reflect.StructTag(st.Tag(i)).Lookup("art")
+		if !ok {
+			continue
+		}
+
+		p, opt, _ := strings.Cut(tag, ",")
+		pos, err := strconv.Atoi(p)
+		if err != nil || (opt != "" && opt != "desc") {
+			return key, fmt.Errorf("%s: invalid art tag %q", typ, tag)
+		}
+
+		base, err := baseType(field.Type())
+		if err != nil {
+			return key, fmt.Errorf("%s: %v", typ, err)
+		}
+
+		fieldType := types.TypeString(field.Type(), qualifier)
+		key.Fields = append(key.Fields, KeyField{
+			Name:    field.Name(),
+			Encoder: encoder(base, opt == "desc"),
+			Base:    base,
+			Type:    fieldType,
+			Convert: fieldType != base,
+			pos:     pos,
+		})
+	}
+
+	if len(key.Fields) == 0 {
+		return key, fmt.Errorf("%s has no field tagged with art", typ)
+	}
+
+	slices.SortFunc(key.Fields, func(a, b KeyField) int { return a.pos - b.pos })
+	for i := 1; i < len(key.Fields); i++ {
+		if key.Fields[i-1].pos == key.Fields[i].pos {
+			return key, fmt.Errorf("%s has two fields at position %d", typ, key.Fields[i].pos)
+		}
+	}
+	return key, nil
+}
+
+// baseType returns the builtin type underlying a field type, such as uint64
+// for a type UserID uint64, which is the type argument of its encoder.
+func baseType(t types.Type) (string, error) {
+	switch u := t.Underlying().(type) {
+	case *types.Basic:
+		switch u.Kind() {
+		case types.Uint, types.Uint8, types.Uint16, types.Uint32, types.Uint64,
+			types.Int, types.Int8, types.Int16, types.Int32, types.Int64,
+			types.Float32, types.Float64, types.String:
+			return types.Typ[u.Kind()].Name(), nil
+		}
+	case *types.Slice:
+		if elt, ok := u.Elem().Underlying().(*types.Basic); ok && elt.Kind() == types.Uint8 {
+			return "[]byte", nil
+		}
+	}
+	return "", fmt.Errorf("unsupported field type %s", t)
+}
+
+// encoder returns the expression of the encoder of a builtin type.
+func encoder(typ string, desc bool) string {
+	var enc string
+	switch typ {
+	case "uint", "uint8", "uint16", "uint32", "uint64":
+		enc = fmt.Sprintf("art.UnsignedBinaryKey[%s]{}", typ)
+	case "int", "int8", "int16", "int32", "int64":
+		enc = fmt.Sprintf("art.SignedBinaryKey[%s]{}", typ)
+	case "float32", "float64":
+		enc = fmt.Sprintf("art.FloatBinaryKey[%s]{}", typ)
+	case "string", "[]byte":
+		if desc {
+			return fmt.Sprintf("art.Desc[%s]{Key: art.AlphabeticalOrderKey[%s]{}}", typ, typ)
+		}
+		return fmt.Sprintf("art.EscapedBinaryKey[%s]{}", typ)
+	}
+
+	if desc {
+		return fmt.Sprintf("art.Desc[%s]{Key: %s}", typ, enc)
+	}
+	return enc
+}
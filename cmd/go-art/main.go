@@ -2,8 +2,10 @@ package main
 
 import (
 	"embed"
+	"flag"
 	"log"
 	"os"
+	"strings"
 	"text/template"
 )
 
@@ -14,22 +16,35 @@ type Tree struct {
 	KeysConstraint              string
 	Name                        string
 	NodeName                    string
-	KeyName                     string
-	AddNullByte                 bool
+	Codec                       string
 	ComparableKeys, CompoundKey bool
 
 	HasPrefix bool
+
+	// InlineKeySize is the size of the keys stored in the leaves instead of
+	// being referenced. It is 0 for variable-length keys.
+	InlineKeySize int
 }
 
 func main() {
+	typeNames := flag.String("type", "", "comma-separated list of structs to generate a key for")
+	output := flag.String("output", "", "output file name; default <type>_key.go")
+	flag.Parse()
+
+	if *typeNames != "" {
+		if err := generateKeys(strings.Split(*typeNames, ","), *output); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
 	trees := []Tree{
 		{
 			KeysConstraint: "chars",
 			Name:           "alphaSortedTree",
 			NodeName:       "alphaLeafNode",
-			KeyName:        "AlphabeticalOrderKey",
+			Codec:          "alphaCodec",
 
-			AddNullByte:    true,
 			ComparableKeys: false,
 			HasPrefix:      true,
 			CompoundKey:    false,
@@ -38,9 +53,8 @@ func main() {
 			KeysConstraint: "uints",
 			Name:           "unsignedSortedTree",
 			NodeName:       "unsignedLeafNode",
-			KeyName:        "UnsignedBinaryKey",
+			Codec:          "unsignedCodec",
 
-			AddNullByte:    false,
 			ComparableKeys: true,
 			CompoundKey:    false,
 		},
@@ -48,9 +62,8 @@ func main() {
 			KeysConstraint: "ints",
 			Name:           "signedSortedTree",
 			NodeName:       "signedLeafNode",
-			KeyName:        "SignedBinaryKey",
+			Codec:          "signedCodec",
 
-			AddNullByte:    false,
 			ComparableKeys: true,
 			CompoundKey:    false,
 		},
@@ -58,9 +71,8 @@ func main() {
 			KeysConstraint: "floats",
 			Name:           "floatSortedTree",
 			NodeName:       "floatLeafNode",
-			KeyName:        "FloatBinaryKey",
+			Codec:          "floatCodec",
 
-			AddNullByte:    false,
 			ComparableKeys: true,
 			CompoundKey:    false,
 		},
@@ -68,12 +80,22 @@ func main() {
 			KeysConstraint: "any",
 			Name:           "compoundSortedTree",
 			NodeName:       "compoundLeafNode",
-			KeyName:        "BinaryComparableKey",
+			Codec:          "compoundCodec",
 
-			AddNullByte:    false,
 			ComparableKeys: false,
+			HasPrefix:      true,
 			CompoundKey:    true,
 		},
+		{
+			KeysConstraint: "~[16]byte",
+			Name:           "uuidSortedTree",
+			NodeName:       "uuidLeafNode",
+			Codec:          "uuidCodec",
+
+			ComparableKeys: true,
+			CompoundKey:    false,
+			InlineKeySize:  16,
+		},
 	}
 
 	tmpl, err := template.ParseFS(codeTmpl, "tree.tmpl")
@@ -81,7 +103,7 @@ func main() {
 		panic(err)
 	}
 
-	file, err := os.OpenFile("trees.go", os.O_CREATE|os.O_WRONLY, 0644)
+	file, err := os.OpenFile("trees.go", os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
 	if err != nil {
 		log.Fatal(err)
 	}
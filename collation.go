@@ -3,7 +3,8 @@ package art
 import (
 	"bytes"
 	"iter"
-	"strings"
+	"sort"
+	"sync/atomic"
 	"unsafe"
 
 	"golang.org/x/text/collate"
@@ -25,9 +26,27 @@ type collationSortedTree[K chars | []rune, V any] struct {
 	cok  CollationOrderKey[K]
 	root nodeRef
 	size int
+
+	// dedupe makes the keys with the same collation key the same key.
+	dedupe bool
+
+	overflows atomic.Int64
 }
 
-func NewCollationSortedTree[K chars | []rune, V any](opts ...func(*collationSortedTree[K, V])) Tree[K, V] {
+// CollationTree is a Tree ordered by collation keys.
+type CollationTree[K chars | []rune, V any] interface {
+	Tree[K, V]
+
+	// SearchEquivalent iterates over the keys with the same collation key as
+	// the given key, e.g. "Résumé" for "resume" with a collator ignoring case
+	// and accents.
+	SearchEquivalent(K) iter.Seq2[K, V]
+}
+
+// NewCollationSortedTree returns a tree ordered by the collation keys of the
+// keys. Keys with the same collation key but different bytes are distinct
+// keys, ordered by their bytes, unless WithDedupe is used.
+func NewCollationSortedTree[K chars | []rune, V any](opts ...func(*collationSortedTree[K, V])) CollationTree[K, V] {
 	t := &collationSortedTree[K, V]{
 		cok: CollationOrderKey[K]{
 			c:   collate.New(language.Und),
@@ -48,6 +67,78 @@ func WithCollator[K chars, V any](c *collate.Collator) func(*collationSortedTree
 	}
 }
 
+// WithDedupe makes the keys with the same collation key the same key: Search
+// and Delete find a key by any of its equivalents and Insert replaces the
+// equivalent key with the inserted one.
+func WithDedupe[K chars, V any]() func(*collationSortedTree[K, V]) {
+	return func(t *collationSortedTree[K, V]) {
+		t.dedupe = true
+	}
+}
+
+// primaryWeights returns the first level of a collation key. Primary weights
+// are encoded on 2 bytes, or 3 bytes when the high bit of the first one is set,
+// and the level ends with a 0x0000 separator. It reports false when there is
+// no separator.
+func primaryWeights(colKey []byte) ([]byte, bool) {
+	i := 0
+	for i+1 < len(colKey) {
+		if colKey[i] == 0 && colKey[i+1] == 0 {
+			return colKey[:i], true
+		}
+
+		if colKey[i]&0x80 != 0 {
+			i += 3
+		} else {
+			i += 2
+		}
+	}
+	return nil, false
+}
+
+// encodeKey returns the key stored in the tree: the escaped collation key,
+// followed by the escaped bytes of the key unless the tree dedupes. Both are
+// terminated, which keeps the stored keys prefix-free.
+func (t *collationSortedTree[K, V]) encodeKey(key K) (keyS, ikey []byte) {
+	keyS, colKey := t.cok.Transform(key)
+
+	ikey = appendEscaped(make([]byte, 0, len(colKey)+len(keyS)+4), colKey)
+	ikey = append(ikey, escapeByte, endByte)
+	if !t.dedupe {
+		ikey = appendEscaped(ikey, keyS)
+		ikey = append(ikey, escapeByte, endByte)
+	}
+
+	t.cok.buf.Reset()
+	return keyS, ikey
+}
+
+// encodeCollation returns the escaped collation key of key, the stored keys
+// of its equivalents start with it followed by 0x00 0x01.
+func (t *collationSortedTree[K, V]) encodeCollation(key K) []byte {
+	_, colKey := t.cok.Transform(key)
+	b := appendEscaped(make([]byte, 0, len(colKey)+2), colKey)
+	t.cok.buf.Reset()
+	return b
+}
+
+// startBound and endBound turn the escaped collation key of a bound into a
+// bound of the stored keys: the equivalents of an inclusive bound are in the
+// range and those of an exclusive bound aren't.
+func startBound(b []byte, kind boundKind) []byte {
+	if kind == exclusive {
+		return append(b, escapeByte, endByte+1)
+	}
+	return append(b, escapeByte, endByte)
+}
+
+func endBound(b []byte, kind boundKind) []byte {
+	if kind == exclusive {
+		return append(b, escapeByte, endByte)
+	}
+	return append(b, escapeByte, endByte+1)
+}
+
 func (t *collationSortedTree[K, V]) restoreKey(ptr unsafe.Pointer) (K, V) {
 	l := (*collateLeafNode[V])(ptr)
 	return K(string(l.getKey())), l.value
@@ -73,7 +164,7 @@ func (t *collationSortedTree[K, V]) Delete(key K) bool {
 		return false
 	}
 
-	keyS, colKey := t.cok.Transform(key)
+	_, ikey := t.encodeKey(key)
 
 	ref := &t.root
 	n := *ref
@@ -83,7 +174,7 @@ func (t *collationSortedTree[K, V]) Delete(key K) bool {
 		if n.tag == nodeKindLeaf {
 			leaf := (*collateLeafNode[V])(n.pointer)
 
-			if bytes.Equal(leaf.getKey(), keyS) {
+			if bytes.Equal(leaf.getTransformKey(), ikey) {
 				*ref = nodeRef{}
 				t.size--
 				return true
@@ -94,14 +185,14 @@ func (t *collationSortedTree[K, V]) Delete(key K) bool {
 
 		node := n.node()
 		if node.prefixLen != 0 {
-			prefixLen := node.checkPrefix(colKey, depth)
+			prefixLen := node.checkPrefix(ikey, depth)
 			if prefixLen != int(min(maxPrefixLen, node.prefixLen)) {
 				return false
 			}
 			depth += int(node.prefixLen)
 		}
 
-		child := n.findChild(colKey[depth])
+		child := n.findChild(ikey[depth])
 
 		if child == nil {
 			return false
@@ -110,8 +201,8 @@ func (t *collationSortedTree[K, V]) Delete(key K) bool {
 		if child.tag == nodeKindLeaf {
 			leaf := (*collateLeafNode[V])(child.pointer)
 
-			if bytes.Equal(leaf.getKey(), keyS) {
-				ref.deleteChild(colKey[depth])
+			if bytes.Equal(leaf.getTransformKey(), ikey) {
+				ref.deleteChild(ikey[depth])
 				t.size--
 				return true
 			}
@@ -128,15 +219,15 @@ func (t *collationSortedTree[K, V]) Delete(key K) bool {
 
 // Insert inserts a key-value pair in the tree.
 func (t *collationSortedTree[K, V]) Insert(key K, val V) {
-	keyS, colKey := t.cok.Transform(key)
+	keyS, ikey := t.encodeKey(key)
 
 	createLeaf := func() unsafe.Pointer {
 		return unsafe.Pointer(&collateLeafNode[V]{
-			colKey:    unsafe.SliceData(colKey),
+			colKey:    unsafe.SliceData(ikey),
 			key:       unsafe.SliceData(keyS),
 			value:     val,
 			keyLen:    uint32(len(keyS)),
-			colKeyLen: uint32(len(colKey)),
+			colKeyLen: uint32(len(ikey)),
 		})
 	}
 
@@ -154,7 +245,8 @@ func (t *collationSortedTree[K, V]) Insert(key K, val V) {
 		if ref.tag == nodeKindLeaf {
 			nl := (*collateLeafNode[V])(ref.pointer)
 
-			if bytes.Equal(keyS, nl.getKey()) {
+			if bytes.Equal(ikey, nl.getTransformKey()) {
+				nl.key, nl.keyLen = unsafe.SliceData(keyS), uint32(len(keyS))
 				nl.value = val
 				return
 			}
@@ -162,10 +254,10 @@ func (t *collationSortedTree[K, V]) Insert(key K, val V) {
 			leafKey := nl.getTransformKey()
 			newNode := nodePools[nodeKind4].Get().(*node4)
 
-			longestPrefix := longestCommonPrefix(leafKey, colKey, depth)
+			longestPrefix := longestCommonPrefix(leafKey, ikey, depth)
 			newNode.prefixLen = uint32(longestPrefix)
 
-			copy(newNode.prefix[:], colKey[depth:])
+			copy(newNode.prefix[:], ikey[depth:])
 
 			*ref = nodeRef{pointer: unsafe.Pointer(newNode), tag: nodeKind4}
 
@@ -174,9 +266,9 @@ func (t *collationSortedTree[K, V]) Insert(key K, val V) {
 				newNode.addChild(ref, leafKey[splitPrefix], n)
 			}
 
-			if splitPrefix < len(colKey) {
+			if splitPrefix < len(ikey) {
 				leafRef := nodeRef{pointer: createLeaf(), tag: nodeKindLeaf}
-				newNode.addChild(ref, colKey[splitPrefix], leafRef)
+				newNode.addChild(ref, ikey[splitPrefix], leafRef)
 			}
 			t.size++
 			return
@@ -184,7 +276,7 @@ func (t *collationSortedTree[K, V]) Insert(key K, val V) {
 
 		node := ref.node()
 		if node.prefixLen != 0 {
-			prefixDiff := prefixMismatch[V, *collateLeafNode[V]](n, colKey, depth)
+			prefixDiff := prefixMismatch[V, *collateLeafNode[V]](n, ikey, depth, &t.overflows)
 
 			if prefixDiff >= int(node.prefixLen) {
 				depth += int(node.prefixLen)
@@ -205,6 +297,7 @@ func (t *collationSortedTree[K, V]) Insert(key K, val V) {
 				copy(node.prefix[:], node.prefix[loLimit:])
 			} else {
 				node.prefixLen -= uint32(prefixDiff + 1)
+				t.overflows.Add(1) // loading the rest of the path
 				leafMin := (*collateLeafNode[V])(minimum[V](n))
 				leafKey := leafMin.getTransformKey()
 
@@ -213,21 +306,21 @@ func (t *collationSortedTree[K, V]) Insert(key K, val V) {
 				copy(node.prefix[:], leafKey[loLimit:])
 			}
 
-			if depth+prefixDiff >= len(colKey) {
+			if depth+prefixDiff >= len(ikey) {
 				return
 			}
 			leafRef := nodeRef{pointer: createLeaf(), tag: nodeKindLeaf}
-			newNode.addChild(ref, colKey[depth+prefixDiff], leafRef)
+			newNode.addChild(ref, ikey[depth+prefixDiff], leafRef)
 			t.size++
 			return
 		}
 
 	CONTINUE_SEARCH:
-		if depth >= len(colKey) {
+		if depth >= len(ikey) {
 			return
 		}
 
-		child := ref.findChild(colKey[depth])
+		child := ref.findChild(ikey[depth])
 		if child != nil {
 			n = *child
 			ref = child
@@ -236,7 +329,7 @@ func (t *collationSortedTree[K, V]) Insert(key K, val V) {
 		}
 
 		leafRef := nodeRef{pointer: createLeaf(), tag: nodeKindLeaf}
-		ref.addChild(colKey[depth], leafRef)
+		ref.addChild(ikey[depth], leafRef)
 		t.size++
 		return
 	}
@@ -268,45 +361,99 @@ func (t *collationSortedTree[K, V]) Minimum() (K, V, bool) {
 	return notFoundKey, notFoundValue, false
 }
 
+// Prefix returns an iterator over the keys starting with p, in collation order.
 func (t *collationSortedTree[K, V]) Prefix(p K) iter.Seq2[K, V] {
-	if len(p) == 0 {
-		return t.All()
-	}
+	return t.prefix(p, all[K, V])
+}
 
-	keyS, colKey := t.cok.Transform(p)
+// PrefixBackward returns an iterator over the keys starting with p, in reverse
+// collation order.
+func (t *collationSortedTree[K, V]) PrefixBackward(p K) iter.Seq2[K, V] {
+	return t.prefix(p, backward[K, V])
+}
 
+// prefix descends to the subtree sharing the primary weights of p. These are a
+// prefix of the primary weights of every key starting with p (contractions
+// aside) but the subtree also holds keys only differing at the other levels,
+// so the keys still need to be checked with hasCollationPrefix. Without
+// primary weights, e.g. for an empty p, every key is checked.
+func (t *collationSortedTree[K, V]) prefix(
+	p K,
+	walk func(nodeRef, func(unsafe.Pointer) (K, V)) iter.Seq2[K, V],
+) iter.Seq2[K, V] {
+	_, colKey := t.cok.Transform(p)
+	colKey = bytes.Clone(colKey)
+	t.cok.buf.Reset()
 	root := t.root
-	if t.root.pointer != nil {
-		root = lowestCommonParent[V, *collateLeafNode[V]](root, colKey)
+	if primary, ok := primaryWeights(colKey); ok {
+		root = prefixRoot[V, *collateLeafNode[V]](t.root, appendEscaped(nil, primary), &t.overflows)
 	}
 
-	hasPrefix := func(k K, v V) bool {
-		leafKeyS := []byte(string(k))
-		return bytes.HasPrefix(leafKeyS, keyS)
+	return func(yield func(K, V) bool) {
+		buf := &collate.Buffer{}
+		var bounds []int
+		for k, v := range walk(root, t.restoreKey) {
+			key := []byte(string(k))
+
+			bounds = bounds[:0]
+			for i := range string(key) {
+				bounds = append(bounds, i)
+			}
+			bounds = append(bounds, len(key))
+
+			if !t.hasCollationPrefix(buf, key, colKey, bounds) {
+				continue
+			}
+
+			if !yield(k, v) {
+				return
+			}
+		}
 	}
-	return filter(root, hasPrefix, t.restoreKey)
 }
 
-func (t *collationSortedTree[K, V]) Range(start, end K) iter.Seq2[K, V] {
-	if len(end) == 0 {
-		end, _ = t.restoreKey(maximum[V](t.root))
-	}
+// hasCollationPrefix reports whether key starts with a prefix whose collation
+// key is colKey. The collation key of a prefix isn't a prefix of the
+// collation key of the whole key, each level being followed by the next
+// ones, but it sorts before it. So the collation keys of the leading parts of
+// key grow with their length and the prefix is binary searched among the
+// rune boundaries of key, collating O(log n) leading parts.
+func (t *collationSortedTree[K, V]) hasCollationPrefix(buf *collate.Buffer, key, colKey []byte, bounds []int) bool {
+	_, found := sort.Find(len(bounds), func(i int) int {
+		c := bytes.Compare(colKey, t.cok.c.Key(buf, key[:bounds[i]]))
+		buf.Reset()
+		return c
+	})
+	return found
+}
 
-	if strings.Compare(string(start), string(end)) > 0 { // start > end
-		// IDEA: maybe do the iteration in reverse instead?
-		start, end = end, start
+func (t *collationSortedTree[K, V]) Range(start, end K) iter.Seq2[K, V] {
+	startKey, endKey := t.encodeCollation(start), t.encodeCollation(end)
+	if len(end) != 0 && bytes.Compare(startKey, endKey) > 0 {
+		startKey, endKey = endKey, startKey
 	}
 
-	startKey, startColKey := t.cok.Transform(start)
-	endKey, endColKey := t.cok.Transform(end)
+	bounds := rangeBounds(startBound(startKey, inclusive), endBound(endKey, inclusive), len(end) == 0)
+	return rangeScan[K, V, *collateLeafNode[V]](t.root, bounds, t.restoreKey, &t.overflows)
+}
 
-	return rangeScan[K, V, *collateLeafNode[V]](t.root, startKey, endKey, startColKey, endColKey, t.restoreKey)
+// RangeWith returns an iterator over the keys within the bounds of opts, in
+// collation order.
+func (t *collationSortedTree[K, V]) RangeWith(opts RangeOptions[K]) iter.Seq2[K, V] {
+	bounds := newScanBounds(opts, t.encodeCollation)
+	if bounds.startKind != unbounded {
+		bounds.start = startBound(bounds.start, bounds.startKind)
+	}
+	if bounds.endKind != unbounded {
+		bounds.end = endBound(bounds.end, bounds.endKind)
+	}
+	return rangeScan[K, V, *collateLeafNode[V]](t.root, bounds, t.restoreKey, &t.overflows)
 }
 
 // Search searches for element with the given key.
 // It returns whether the key is present (bool) and its value if it is present.
 func (t *collationSortedTree[K, V]) Search(key K) (V, bool) {
-	keyS, colKey := t.cok.Transform(key)
+	_, ikey := t.encodeKey(key)
 
 	var notFound V
 
@@ -317,7 +464,7 @@ func (t *collationSortedTree[K, V]) Search(key K) (V, bool) {
 		if n.tag == nodeKindLeaf {
 			leaf := (*collateLeafNode[V])(n.pointer)
 
-			if bytes.Equal(leaf.getKey(), keyS) {
+			if bytes.Equal(leaf.getTransformKey(), ikey) {
 				return leaf.value, true
 			}
 			return notFound, false
@@ -325,7 +472,7 @@ func (t *collationSortedTree[K, V]) Search(key K) (V, bool) {
 
 		node := n.node()
 		if node.prefixLen != 0 {
-			prefixLen := node.checkPrefix(colKey, depth)
+			prefixLen := node.checkPrefix(ikey, depth)
 
 			if prefixLen != int(min(maxPrefixLen, node.prefixLen)) {
 				return notFound, false
@@ -334,7 +481,7 @@ func (t *collationSortedTree[K, V]) Search(key K) (V, bool) {
 			depth += int(node.prefixLen)
 		}
 
-		b := colKey[depth]
+		b := ikey[depth]
 		switch n.tag {
 		case nodeKind4:
 			n4 := (*node4)(n.pointer)
@@ -382,8 +529,22 @@ func (t *collationSortedTree[K, V]) Search(key K) (V, bool) {
 	return notFound, false
 }
 
+// SearchEquivalent returns an iterator over the keys with the same collation
+// key as key, in collation order.
+func (t *collationSortedTree[K, V]) SearchEquivalent(key K) iter.Seq2[K, V] {
+	root := prefixRoot[V, *collateLeafNode[V]](t.root, startBound(t.encodeCollation(key), inclusive), &t.overflows)
+	return all(root, t.restoreKey)
+}
+
 func (t *collationSortedTree[K, V]) TopK(k uint) iter.Seq2[K, V] {
 	return topK(t, k)
 }
 
 func (t *collationSortedTree[K, V]) Size() int { return t.size }
+
+// Stats walks the tree and reports its shape and memory footprint.
+func (t *collationSortedTree[K, V]) Stats() Stats {
+	s := stats[V, *collateLeafNode[V]](t.root, unsafe.Sizeof(collateLeafNode[V]{}))
+	s.OverflowLookups = int(t.overflows.Load())
+	return s
+}
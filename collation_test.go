@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"slices"
+	"strings"
 	"testing"
 
 	"github.com/Clement-Jean/go-art"
@@ -640,3 +641,180 @@ func TestCollationRange(t *testing.T) {
 		})
 	}
 }
+
+func TestCollatePrefixBackward(t *testing.T) {
+	tr := art.NewCollationSortedTree[string, int]()
+	keys := []string{"api.foo.bar", "api.foo.baz", "api.foe.fum", "abc.123.456", "api.foo", "api"}
+
+	for i, k := range keys {
+		tr.Insert(k, i)
+	}
+
+	var got []string
+	for k, _ := range tr.PrefixBackward("api.foo") {
+		got = append(got, k)
+	}
+
+	if expected := []string{"api.foo.baz", "api.foo.bar", "api.foo"}; !slices.Equal(expected, got) {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+}
+
+func TestCollatePrefixInsensitive(t *testing.T) {
+	col := collate.New(language.English, collate.IgnoreCase, collate.IgnoreDiacritics)
+	tr := art.NewCollationSortedTree(art.WithCollator[string, int](col))
+
+	for i, k := range []string{"Résumé", "resumes", "rest", "Réservoir", "RESULT", "rescue", "ré"} {
+		tr.Insert(k, i)
+	}
+
+	tests := []struct {
+		prefix   string
+		expected []string
+	}{
+		{"resu", []string{"RESULT", "Résumé", "resumes"}},
+		{"RÉSU", []string{"RESULT", "Résumé", "resumes"}},
+		{"rés", []string{"rescue", "Réservoir", "rest", "RESULT", "Résumé", "resumes"}},
+		{"re", []string{"ré", "rescue", "Réservoir", "rest", "RESULT", "Résumé", "resumes"}},
+		{"résumés", []string{"resumes"}},
+		{"resv", []string{}},
+	}
+
+	for _, tt := range tests {
+		got := []string{}
+		for k, _ := range tr.Prefix(tt.prefix) {
+			got = append(got, k)
+		}
+
+		if !slices.Equal(tt.expected, got) {
+			t.Fatalf("%q: expected %v, got %v", tt.prefix, tt.expected, got)
+		}
+	}
+}
+
+func TestCollatePrefixStrength(t *testing.T) {
+	tr := art.NewCollationSortedTree[string, int]()
+
+	for i, k := range []string{"Résumé", "resume", "RESUME"} {
+		tr.Insert(k, i)
+	}
+
+	var got []string
+	for k, _ := range tr.Prefix("resu") {
+		got = append(got, k)
+	}
+
+	if expected := []string{"resume"}; !slices.Equal(expected, got) {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+}
+
+func TestCollatePrefixLongKeys(t *testing.T) {
+	col := collate.New(language.English, collate.IgnoreCase)
+	tr := art.NewCollationSortedTree(art.WithCollator[string, int](col))
+
+	long := strings.Repeat("abc", 2000)
+	for i, k := range []string{long, long + "d", strings.ToUpper(long[:3000]) + "x", long[:2999] + "x"} {
+		tr.Insert(k, i)
+	}
+
+	got := []int{}
+	for _, v := range tr.Prefix(long[:3000]) {
+		got = append(got, v)
+	}
+
+	slices.Sort(got)
+	if expected := []int{0, 1, 2}; !slices.Equal(expected, got) {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+}
+
+func TestCollateSearchEquivalent(t *testing.T) {
+	col := collate.New(language.English, collate.IgnoreCase, collate.IgnoreDiacritics)
+	tr := art.NewCollationSortedTree(art.WithCollator[string, int](col))
+
+	for i, k := range []string{"Résumé", "resumes", "resume", "RESUME", "rest"} {
+		tr.Insert(k, i)
+	}
+
+	if tr.Size() != 5 {
+		t.Fatalf("expected 5 distinct keys, got %d", tr.Size())
+	}
+
+	if v, ok := tr.Search("RESUME"); !ok || v != 3 {
+		t.Fatalf("expected RESUME to be found with 3, got %d", v)
+	}
+	if _, ok := tr.Search("résumé"); ok {
+		t.Fatal("expected Search to only find the exact key")
+	}
+
+	var got []string
+	for k, _ := range tr.SearchEquivalent("résumé") {
+		got = append(got, k)
+	}
+
+	// ordered by bytes among the equivalents
+	if expected := []string{"RESUME", "Résumé", "resume"}; !slices.Equal(expected, got) {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+
+	got = got[:0]
+	for k, _ := range tr.Range("rest", "resume") {
+		got = append(got, k)
+	}
+
+	if expected := []string{"rest", "RESUME", "Résumé", "resume"}; !slices.Equal(expected, got) {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+
+	got = got[:0]
+	for k, _ := range tr.RangeWith(art.RangeOptions[string]{Start: art.Exclusive("resume")}) {
+		got = append(got, k)
+	}
+
+	if expected := []string{"resumes"}; !slices.Equal(expected, got) {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+
+	if !tr.Delete("Résumé") || tr.Delete("résumé") {
+		t.Fatal("expected Delete to only delete the exact key")
+	}
+}
+
+func TestCollateDedupe(t *testing.T) {
+	col := collate.New(language.English, collate.IgnoreCase, collate.IgnoreDiacritics)
+	tr := art.NewCollationSortedTree(
+		art.WithCollator[string, int](col),
+		art.WithDedupe[string, int](),
+	)
+
+	tr.Insert("resume", 1)
+	tr.Insert("Résumé", 2)
+	tr.Insert("resumes", 3)
+
+	if tr.Size() != 2 {
+		t.Fatalf("expected 2 keys, got %d", tr.Size())
+	}
+
+	if v, ok := tr.Search("RESUME"); !ok || v != 2 {
+		t.Fatalf("expected RESUME to be found with 2, got %d", v)
+	}
+
+	k, _, _ := tr.Minimum()
+	if k != "Résumé" {
+		t.Fatalf("expected the last inserted spelling, got %q", k)
+	}
+
+	var got []string
+	for k, _ := range tr.SearchEquivalent("resume") {
+		got = append(got, k)
+	}
+
+	if expected := []string{"Résumé"}; !slices.Equal(expected, got) {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+
+	if !tr.Delete("RÉSUMÉ") || tr.Size() != 1 {
+		t.Fatal("expected an equivalent key to delete Résumé")
+	}
+}
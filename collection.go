@@ -0,0 +1,331 @@
+package art
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"iter"
+)
+
+// ErrDuplicateKey is returned by IndexedCollection.Insert when a record has
+// the key of another record in a unique index.
+var ErrDuplicateKey = errors.New("art: duplicate key")
+
+// IndexedCollection stores records under a primary key of type K and keeps
+// secondary indexes of the records consistent with them. The records and
+// every index are compound trees, the index keys being encoded with the
+// BinaryComparableKey given to UniqueIndex or NonUniqueIndex.
+//
+// The indexes are queried through the IndexOf returned by UniqueIndex and
+// NonUniqueIndex, or by name with Find, All and Range. The latter take the
+// keys as any, and panic when the index doesn't exist or when the key
+// doesn't have the type of the index.
+type IndexedCollection[T, K any] struct {
+	records Tree[[]byte, T]
+	primary *IndexOf[T, K]
+
+	indexes []*collectionIndex[T]
+	byName  map[string]*collectionIndex[T]
+}
+
+// Index is a secondary index of an IndexedCollection, as returned by
+// UniqueIndex and NonUniqueIndex.
+type Index[T any] interface {
+	base() *collectionIndex[T]
+}
+
+// IndexOf is an index of the records by keys of type K. It can only be
+// queried once given to NewIndexedCollection, and only belongs to one
+// collection.
+type IndexOf[T, K any] struct {
+	collectionIndex[T]
+	bck BinaryComparableKey[K]
+}
+
+type collectionIndex[T any] struct {
+	name   string
+	unique bool
+	record func(T) []byte
+	encode func(any) []byte
+
+	// tree maps the index keys, followed by the primary keys when the
+	// index isn't unique, to the primary keys. It is nil for the primary
+	// index, whose keys are the ones of records.
+	tree    Tree[[]byte, []byte]
+	records Tree[[]byte, T]
+}
+
+func (idx *IndexOf[T, K]) base() *collectionIndex[T] { return &idx.collectionIndex }
+
+// UniqueIndex returns an index of the records by the key extracted by key.
+// No two records can have the same key.
+func UniqueIndex[T, K any](name string, bck BinaryComparableKey[K], key func(T) K) *IndexOf[T, K] {
+	return newIndex(name, true, bck, key)
+}
+
+// NonUniqueIndex returns an index of the records by the key extracted by key.
+// The records with the same key are ordered by primary key.
+func NonUniqueIndex[T, K any](name string, bck BinaryComparableKey[K], key func(T) K) *IndexOf[T, K] {
+	return newIndex(name, false, bck, key)
+}
+
+func newIndex[T, K any](name string, unique bool, bck BinaryComparableKey[K], key func(T) K) *IndexOf[T, K] {
+	return &IndexOf[T, K]{
+		collectionIndex: collectionIndex[T]{
+			name:   name,
+			unique: unique,
+			record: func(rec T) []byte { return encodeIndexKey(bck, key(rec)) },
+			encode: func(k any) []byte {
+				typed, ok := k.(K)
+				if !ok {
+					panic(fmt.Sprintf("art: index %q expects keys of type %T, got %T", name, typed, k))
+				}
+				return encodeIndexKey(bck, typed)
+			},
+		},
+		bck: bck,
+	}
+}
+
+// encodeIndexKey escapes the encoding of k so that any BinaryComparableKey
+// can be followed by a primary key or by a terminator. The terminator is
+// added by the callers, the range bounds needing other ones.
+func encodeIndexKey[K any](bck BinaryComparableKey[K], k K) []byte {
+	_, b := bck.Transform(k)
+	return appendEscaped(make([]byte, 0, len(b)+2), b)
+}
+
+func terminated(b []byte) []byte {
+	return append(b[:len(b):len(b)], escapeByte, endByte)
+}
+
+// NewIndexedCollection returns a collection of records identified by the
+// primary key extracted by key, with the given secondary indexes.
+func NewIndexedCollection[T, K any](bck BinaryComparableKey[K], key func(T) K, indexes ...Index[T]) *IndexedCollection[T, K] {
+	c := &IndexedCollection[T, K]{
+		records: NewCompoundTree[[]byte, T](AlphabeticalOrderKey[[]byte]{}),
+		primary: newIndex("", true, bck, key),
+		byName:  make(map[string]*collectionIndex[T], len(indexes)),
+	}
+	c.primary.records = c.records
+
+	for _, i := range indexes {
+		idx := i.base()
+		if _, ok := c.byName[idx.name]; ok || idx.name == "" {
+			panic(fmt.Sprintf("art: invalid or duplicate index name %q", idx.name))
+		}
+		if idx.records != nil {
+			panic(fmt.Sprintf("art: index %q is already in a collection", idx.name))
+		}
+
+		idx.tree = NewCompoundTree[[]byte, []byte](AlphabeticalOrderKey[[]byte]{})
+		idx.records = c.records
+		c.indexes = append(c.indexes, idx)
+		c.byName[idx.name] = idx
+	}
+	return c
+}
+
+// entryKey returns the key of a record in the tree of the index.
+func (idx *collectionIndex[T]) entryKey(rec T, pk []byte) []byte {
+	k := terminated(idx.record(rec))
+	if !idx.unique {
+		k = append(k, pk...)
+	}
+	return k
+}
+
+// Insert inserts a record, or replaces the record with the same primary key
+// and updates the indexes. It returns an error wrapping ErrDuplicateKey and
+// leaves the collection unchanged when a unique index already has the key of
+// the record for another record.
+func (c *IndexedCollection[T, K]) Insert(rec T) error {
+	pk := terminated(c.primary.record(rec))
+
+	keys := make([][]byte, len(c.indexes))
+	for i, idx := range c.indexes {
+		keys[i] = idx.entryKey(rec, pk)
+		if !idx.unique {
+			continue
+		}
+
+		if owner, ok := idx.tree.Search(keys[i]); ok && !bytes.Equal(owner, pk) {
+			return fmt.Errorf("%w in index %q", ErrDuplicateKey, idx.name)
+		}
+	}
+
+	if old, ok := c.records.Search(pk); ok {
+		c.unindex(old, pk)
+	}
+
+	c.records.Insert(pk, rec)
+	for i, idx := range c.indexes {
+		idx.tree.Insert(keys[i], pk)
+	}
+	return nil
+}
+
+func (c *IndexedCollection[T, K]) unindex(rec T, pk []byte) {
+	for _, idx := range c.indexes {
+		idx.tree.Delete(idx.entryKey(rec, pk))
+	}
+}
+
+// Get returns the record with the given primary key.
+func (c *IndexedCollection[T, K]) Get(key K) (T, bool) {
+	return c.records.Search(terminated(encodeIndexKey(c.primary.bck, key)))
+}
+
+// Delete deletes the record with the given primary key and reports whether
+// there was one.
+func (c *IndexedCollection[T, K]) Delete(key K) bool {
+	pk := terminated(encodeIndexKey(c.primary.bck, key))
+
+	rec, ok := c.records.Search(pk)
+	if !ok {
+		return false
+	}
+
+	c.unindex(rec, pk)
+	return c.records.Delete(pk)
+}
+
+// Size returns the number of records.
+func (c *IndexedCollection[T, K]) Size() int { return c.records.Size() }
+
+// index returns the index with the given name, or the primary index when
+// name is empty.
+func (c *IndexedCollection[T, K]) index(name string) *collectionIndex[T] {
+	if name == "" {
+		return c.primary.base()
+	}
+
+	idx, ok := c.byName[name]
+	if !ok {
+		panic(fmt.Sprintf("art: unknown index %q", name))
+	}
+	return idx
+}
+
+// Find iterates over the records with the given key in the index, or in the
+// primary key when index is empty, ordered by primary key.
+func (c *IndexedCollection[T, K]) Find(index string, key any) iter.Seq[T] {
+	idx := c.index(index)
+	return idx.find(idx.encode(key))
+}
+
+// All iterates over the records in the order of the index, or of the primary
+// key when index is empty.
+func (c *IndexedCollection[T, K]) All(index string) iter.Seq[T] {
+	return c.index(index).all()
+}
+
+// Range iterates over the records whose key in the index is between start
+// and end included, in the order of the index, or of the primary key when
+// index is empty. A nil start or end is unbounded.
+func (c *IndexedCollection[T, K]) Range(index string, start, end any) iter.Seq[T] {
+	idx := c.index(index)
+
+	var s, e []byte
+	if start != nil {
+		s = idx.encode(start)
+	}
+	if end != nil {
+		e = idx.encode(end)
+	}
+	return idx.scan(s, e)
+}
+
+// Find iterates over the records with the given key, ordered by primary key.
+func (idx *IndexOf[T, K]) Find(key K) iter.Seq[T] {
+	return idx.find(encodeIndexKey(idx.bck, key))
+}
+
+// All iterates over the records in the order of the index.
+func (idx *IndexOf[T, K]) All() iter.Seq[T] {
+	return idx.all()
+}
+
+// Range iterates over the records whose key is between start and end
+// included, in the order of the index.
+func (idx *IndexOf[T, K]) Range(start, end K) iter.Seq[T] {
+	return idx.scan(encodeIndexKey(idx.bck, start), encodeIndexKey(idx.bck, end))
+}
+
+func (idx *collectionIndex[T]) attached() {
+	if idx.records == nil {
+		panic(fmt.Sprintf("art: index %q isn't in a collection", idx.name))
+	}
+}
+
+func (idx *collectionIndex[T]) find(key []byte) iter.Seq[T] {
+	idx.attached()
+	k := terminated(key)
+
+	if idx.tree == nil {
+		return func(yield func(T) bool) {
+			if rec, ok := idx.records.Search(k); ok {
+				yield(rec)
+			}
+		}
+	}
+	if idx.unique {
+		return func(yield func(T) bool) {
+			if pk, ok := idx.tree.Search(k); ok {
+				rec, _ := idx.records.Search(pk)
+				yield(rec)
+			}
+		}
+	}
+	return idx.lookup(idx.tree.Prefix(k))
+}
+
+func (idx *collectionIndex[T]) all() iter.Seq[T] {
+	idx.attached()
+	if idx.tree == nil {
+		return values(idx.records.All())
+	}
+	return idx.lookup(idx.tree.All())
+}
+
+// scan iterates over the records whose encoded key is between start and end
+// included, a nil bound being unbounded. The keys equal to a bound are
+// followed by the terminator and maybe the primary key.
+func (idx *collectionIndex[T]) scan(start, end []byte) iter.Seq[T] {
+	idx.attached()
+
+	var opts RangeOptions[[]byte]
+	if start != nil {
+		opts.Start = Inclusive(startBound(start, inclusive))
+	}
+	if end != nil {
+		opts.End = Exclusive(endBound(end, inclusive))
+	}
+
+	if idx.tree == nil {
+		return values(idx.records.RangeWith(opts))
+	}
+	return idx.lookup(idx.tree.RangeWith(opts))
+}
+
+// lookup iterates over the records of the primary keys of seq.
+func (idx *collectionIndex[T]) lookup(seq iter.Seq2[[]byte, []byte]) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for _, pk := range seq {
+			rec, _ := idx.records.Search(pk)
+			if !yield(rec) {
+				return
+			}
+		}
+	}
+}
+
+func values[K nodeKey, V any](seq iter.Seq2[K, V]) iter.Seq[V] {
+	return func(yield func(V) bool) {
+		for _, v := range seq {
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
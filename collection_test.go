@@ -0,0 +1,177 @@
+package art_test
+
+import (
+	"errors"
+	"iter"
+	"slices"
+	"testing"
+
+	"github.com/Clement-Jean/go-art"
+)
+
+type user struct {
+	ID      uint64
+	Email   string
+	Country string
+	Age     int
+}
+
+func newUsers() *art.IndexedCollection[user, uint64] {
+	return art.NewIndexedCollection(
+		art.UnsignedBinaryKey[uint64]{},
+		func(u user) uint64 { return u.ID },
+		art.UniqueIndex("email", art.AlphabeticalOrderKey[string]{}, func(u user) string { return u.Email }),
+		art.NonUniqueIndex("country", art.AlphabeticalOrderKey[string]{}, func(u user) string { return u.Country }),
+		art.NonUniqueIndex("age", art.SignedBinaryKey[int]{}, func(u user) int { return u.Age }),
+	)
+}
+
+func ids(seq iter.Seq[user]) []uint64 {
+	var got []uint64
+	for u := range seq {
+		got = append(got, u.ID)
+	}
+	return got
+}
+
+func TestIndexedCollection(t *testing.T) {
+	c := newUsers()
+
+	for _, u := range []user{
+		{4, "dan@example.com", "fr", 41},
+		{1, "ann@example.com", "fr", 30},
+		{3, "cid@example.com", "de", 25},
+		{2, "bob@example.com", "french guiana", 30},
+	} {
+		if err := c.Insert(u); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name     string
+		got      []uint64
+		expected []uint64
+	}{
+		{"all", ids(c.All("")), []uint64{1, 2, 3, 4}},
+		{"all by email", ids(c.All("email")), []uint64{1, 2, 3, 4}},
+		{"all by age", ids(c.All("age")), []uint64{3, 1, 2, 4}},
+		{"find email", ids(c.Find("email", "cid@example.com")), []uint64{3}},
+		{"find country", ids(c.Find("country", "fr")), []uint64{1, 4}},
+		{"find missing", ids(c.Find("country", "it")), nil},
+		{"range age", ids(c.Range("age", 25, 30)), []uint64{3, 1, 2}},
+		{"range age from", ids(c.Range("age", 30, nil)), []uint64{1, 2, 4}},
+		{"range country", ids(c.Range("country", "de", "fr")), []uint64{3, 1, 4}},
+		{"range primary", ids(c.Range("", uint64(2), uint64(3))), []uint64{2, 3}},
+	}
+
+	for _, tt := range tests {
+		if !slices.Equal(tt.expected, tt.got) {
+			t.Fatalf("%s: expected %v, got %v", tt.name, tt.expected, tt.got)
+		}
+	}
+}
+
+func TestIndexedCollectionUpdate(t *testing.T) {
+	c := newUsers()
+	c.Insert(user{1, "ann@example.com", "fr", 30})
+	c.Insert(user{2, "bob@example.com", "de", 30})
+
+	// moving ann to another country and email
+	if err := c.Insert(user{1, "ann@example.org", "de", 31}); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := ids(c.Find("email", "ann@example.com")); len(got) != 0 {
+		t.Fatalf("expected the old email to be unindexed, got %v", got)
+	}
+	if got := ids(c.Find("country", "de")); !slices.Equal([]uint64{1, 2}, got) {
+		t.Fatalf("expected [1 2], got %v", got)
+	}
+	if got := ids(c.Find("age", 30)); !slices.Equal([]uint64{2}, got) {
+		t.Fatalf("expected [2], got %v", got)
+	}
+
+	err := c.Insert(user{3, "bob@example.com", "it", 20})
+	if !errors.Is(err, art.ErrDuplicateKey) {
+		t.Fatalf("expected ErrDuplicateKey, got %v", err)
+	}
+	if c.Size() != 2 || len(ids(c.Find("country", "it"))) != 0 {
+		t.Fatal("expected the collection to be unchanged")
+	}
+
+	if !c.Delete(2) {
+		t.Fatal("expected 2 to be deleted")
+	}
+	if c.Delete(2) {
+		t.Fatal("expected 2 to be already deleted")
+	}
+	if got := ids(c.All("age")); !slices.Equal([]uint64{1}, got) {
+		t.Fatalf("expected [1], got %v", got)
+	}
+
+	if err := c.Insert(user{3, "bob@example.com", "it", 20}); err != nil {
+		t.Fatal(err)
+	}
+	if u, ok := c.Get(3); !ok || u.Email != "bob@example.com" {
+		t.Fatalf("expected 3 to be found, got %v", u)
+	}
+}
+
+func TestIndexedCollectionKeyType(t *testing.T) {
+	c := newUsers()
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected a panic for a key of the wrong type")
+		}
+	}()
+	c.Find("age", "30")
+}
+
+func TestIndexedCollectionTypedIndex(t *testing.T) {
+	byEmail := art.UniqueIndex("email", art.AlphabeticalOrderKey[string]{}, func(u user) string { return u.Email })
+	byAge := art.NonUniqueIndex("age", art.SignedBinaryKey[int]{}, func(u user) int { return u.Age })
+	c := art.NewIndexedCollection(art.UnsignedBinaryKey[uint64]{}, func(u user) uint64 { return u.ID }, byEmail, byAge)
+
+	c.Insert(user{1, "ann@example.com", "fr", 30})
+	c.Insert(user{2, "bob@example.com", "de", 25})
+	c.Insert(user{3, "cid@example.com", "de", 41})
+
+	tests := []struct {
+		name     string
+		got      []uint64
+		expected []uint64
+	}{
+		{"find email", ids(byEmail.Find("bob@example.com")), []uint64{2}},
+		{"find age", ids(byAge.Find(30)), []uint64{1}},
+		{"all by age", ids(byAge.All()), []uint64{2, 1, 3}},
+		{"range age", ids(byAge.Range(25, 30)), []uint64{2, 1}},
+		{"by name", ids(c.Find("age", 41)), []uint64{3}},
+	}
+
+	for _, tt := range tests {
+		if !slices.Equal(tt.expected, tt.got) {
+			t.Fatalf("%s: expected %v, got %v", tt.name, tt.expected, tt.got)
+		}
+	}
+
+	// the untyped constants take the type of the primary key
+	if u, ok := c.Get(1); !ok || u.Email != "ann@example.com" {
+		t.Fatalf("expected 1 to be found, got %v", u)
+	}
+	if !c.Delete(1) || len(ids(byEmail.Find("ann@example.com"))) != 0 {
+		t.Fatal("expected 1 to be deleted and unindexed")
+	}
+}
+
+func TestIndexedCollectionDetachedIndex(t *testing.T) {
+	byAge := art.NonUniqueIndex("age", art.SignedBinaryKey[int]{}, func(u user) int { return u.Age })
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected a panic for an index outside of a collection")
+		}
+	}()
+	byAge.Find(30)
+}
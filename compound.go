@@ -1,7 +1,62 @@
 package art
 
-func NewCompoundTree[K any, V any](bck BinaryComparableKey[K]) Tree[K, V] {
+import "bytes"
+
+// NewCompoundTree returns a tree ordering the keys by their encoding with
+// bck. Like with NewAlphaSortedTree, []byte keys are copied by default since
+// their encoding may be the key itself.
+func NewCompoundTree[K any, V any](bck BinaryComparableKey[K], opts ...Option) Tree[K, V] {
 	return &compoundSortedTree[K, V]{
-		bck: bck,
+		compoundCodec: compoundCodec[K]{bck: bck, opts: compoundOptions[K](opts)},
+	}
+}
+
+// NewCompoundSet returns a set ordering the keys like
+// NewCompoundTree.
+func NewCompoundSet[K any](bck BinaryComparableKey[K], opts ...Option) Set[K] {
+	t := &compoundSortedTree[K, struct{}]{
+		compoundCodec: compoundCodec[K]{bck: bck, opts: compoundOptions[K](opts)},
+	}
+	return &keySet[K]{inner: t, encode: t.encodeKey}
+}
+
+func compoundOptions[K any](opts []Option) treeOptions {
+	var k K
+	_, isBytes := any(k).([]byte)
+	return newTreeOptions(treeOptions{keyCopy: isBytes}, opts)
+}
+
+// compoundCodec encodes the keys of the compound trees.
+type compoundCodec[K any] struct {
+	bck  BinaryComparableKey[K]
+	opts treeOptions
+}
+
+func (c *compoundCodec[K]) encodeKey(key K) []byte {
+	_, keyS := c.bck.Transform(key)
+	if c.opts.keyCopy {
+		keyS = bytes.Clone(keyS)
 	}
+	return keyS
+}
+
+// prefixKey is implemented by the encoders terminating their keys, to encode
+// a prefix without the terminator.
+type prefixKey[K any] interface {
+	transformPrefix(K) []byte
+}
+
+// encodePrefix transforms the prefix. It matches the keys whose encoding
+// starts with the encoding of the prefix, e.g. the tuples starting with the
+// elements of a TupleKey prefix.
+func (c *compoundCodec[K]) encodePrefix(p K) []byte {
+	if pk, ok := c.bck.(prefixKey[K]); ok {
+		return pk.transformPrefix(p)
+	}
+	_, prefix := c.bck.Transform(p)
+	return prefix
+}
+
+func (c *compoundCodec[K]) decodeKey(b []byte) K {
+	return c.bck.Restore(b)
 }
@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	"github.com/Clement-Jean/go-art"
+	"github.com/Clement-Jean/go-art/tuple"
 )
 
 type Account struct {
@@ -70,6 +71,26 @@ func TestCompound(t *testing.T) {
 	}
 }
 
+func TestCompoundKeyCopy(t *testing.T) {
+	tr := art.NewCompoundTree[[]byte, int](art.AlphabeticalOrderKey[[]byte]{})
+	buf := []byte("abc")
+
+	tr.Insert(buf, 1)
+	buf[0] = 'z'
+
+	if _, ok := tr.Search([]byte("abc")); !ok {
+		t.Fatal("expected abc to survive the change of the buffer")
+	}
+
+	var got []string
+	for k := range tr.All() {
+		got = append(got, string(k))
+	}
+	if expected := []string{"abc"}; !slices.Equal(expected, got) {
+		t.Fatalf("expected %q, got %q", expected, got)
+	}
+}
+
 func TestCompoundRange(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -139,3 +160,97 @@ func TestCompoundRange(t *testing.T) {
 		})
 	}
 }
+
+func TestCompoundTupleKey(t *testing.T) {
+	tk := art.TupleKey[Account]{
+		ToTuple: func(a Account) tuple.Tuple { return tuple.Tuple{a.name, a.ID} },
+		FromTuple: func(t tuple.Tuple) Account {
+			return Account{name: t[0].(string), ID: uint(t[1].(int64))}
+		},
+	}
+	tr := art.NewCompoundTree[Account, int](tk)
+
+	// a plain concatenation puts {"ab", 1} between {"a", 1} and {"a", 2}
+	accounts := []Account{{2, "a"}, {1, "ab"}, {1, "a"}, {300, "a"}, {0, ""}}
+	for i, acc := range accounts {
+		tr.Insert(acc, i)
+	}
+
+	var got []Account
+	for k, _ := range tr.All() {
+		got = append(got, k)
+	}
+
+	expected := []Account{{0, ""}, {1, "a"}, {2, "a"}, {300, "a"}, {1, "ab"}}
+	if !slices.Equal(expected, got) {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+}
+
+func TestCompoundTupleLengths(t *testing.T) {
+	var tk art.TupleKey[tuple.Tuple]
+	tr := art.NewCompoundTree[tuple.Tuple, int](tk)
+
+	tr.Insert(tuple.Tuple{"a", int64(1)}, 1)
+	tr.Insert(tuple.Tuple{"a"}, 2)
+	tr.Insert(tuple.Tuple{"a", int64(2)}, 3)
+	tr.Insert(tuple.Tuple{"a", int64(1), "x"}, 4)
+	tr.Insert(tuple.Tuple{}, 5)
+
+	var got []string
+	for k, v := range tr.All() {
+		got = append(got, fmt.Sprint(k, v))
+	}
+
+	expected := []string{"[] 5", "[a] 2", "[a 1] 1", "[a 1 x] 4", "[a 2] 3"}
+	if tr.Size() != 5 || !slices.Equal(expected, got) {
+		t.Fatalf("expected %v, got %v (size %d)", expected, got, tr.Size())
+	}
+
+	if v, ok := tr.Search(tuple.Tuple{"a"}); !ok || v != 2 {
+		t.Fatalf("expected {a} to be found, got %d", v)
+	}
+	if _, ok := tr.Search(tuple.Tuple{"a", int64(3)}); ok {
+		t.Fatal("expected {a 3} not to be found")
+	}
+
+	var vals []int
+	for _, v := range tr.Prefix(tuple.Tuple{"a", int64(1)}) {
+		vals = append(vals, v)
+	}
+	if expected := []int{1, 4}; !slices.Equal(expected, vals) {
+		t.Fatalf("expected %v, got %v", expected, vals)
+	}
+
+	if !tr.Delete(tuple.Tuple{"a"}) || tr.Size() != 4 {
+		t.Fatalf("expected {a} to be deleted, got size %d", tr.Size())
+	}
+}
+
+func TestCompoundTuplePrefix(t *testing.T) {
+	var tk art.TupleKey[tuple.Tuple]
+	tr := art.NewCompoundTree[tuple.Tuple, int](tk)
+
+	tr.Insert(tuple.Tuple{"users", int64(1), "alice"}, 1)
+	tr.Insert(tuple.Tuple{"users", int64(1), "bob"}, 2)
+	tr.Insert(tuple.Tuple{"users", int64(10), "carol"}, 3)
+	tr.Insert(tuple.Tuple{"usersx", int64(1), "dave"}, 4)
+
+	var got []int
+	for _, v := range tr.Prefix(tuple.Tuple{"users", int64(1)}) {
+		got = append(got, v)
+	}
+
+	if expected := []int{1, 2}; !slices.Equal(expected, got) {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+
+	got = got[:0]
+	for _, v := range tr.PrefixBackward(tuple.Tuple{"users"}) {
+		got = append(got, v)
+	}
+
+	if expected := []int{3, 2, 1}; !slices.Equal(expected, got) {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+}
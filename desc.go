@@ -0,0 +1,66 @@
+package art
+
+// Desc reverses the order of the keys encoded by Key, e.g. to sort one
+// component of a compound key from the largest to the smallest value.
+//
+// The encoding of the fixed-size keys (UnsignedBinaryKey, SignedBinaryKey
+// and FloatBinaryKey) is simply inverted. Other encodings are escaped and
+// terminated before being inverted, like EscapedBinaryKey, so that a key
+// sorts after the longer keys it is a prefix of.
+type Desc[K nodeKey] struct {
+	Key BinaryComparableKey[K]
+}
+
+// fixedSizeKey is implemented by the encoders producing keys of the same
+// length for every value of a type.
+type fixedSizeKey interface {
+	fixedSize()
+}
+
+func (UnsignedBinaryKey[K]) fixedSize()  {}
+func (SignedBinaryKey[K]) fixedSize()    {}
+func (FloatBinaryKey[K]) fixedSize()     {}
+func (FixedBytesKey[K]) fixedSize()      {}
+func (TotalOrderFloatKey[K]) fixedSize() {}
+
+func (d Desc[K]) Transform(k K) ([]byte, []byte) {
+	_, b := d.Key.Transform(k)
+
+	if _, ok := d.Key.(fixedSizeKey); ok {
+		b = invert(make([]byte, len(b)), b)
+	} else {
+		b = appendEscaped(make([]byte, 0, len(b)+2), b)
+		b = append(b, escapeByte, endByte)
+		invert(b, b)
+	}
+	return b, b
+}
+func (d Desc[K]) Restore(b []byte) K {
+	k, _ := d.RestorePrefix(b)
+	return k
+}
+
+// RestorePrefix restores the key encoded at the start of b and returns it
+// with the number of bytes it took. This is used to decode a Desc component
+// followed by other components.
+func (d Desc[K]) RestorePrefix(b []byte) (K, int) {
+	if _, ok := d.Key.(fixedSizeKey); ok {
+		var zero K
+		_, z := d.Key.Transform(zero)
+		n := len(z)
+		return d.Key.Restore(invert(make([]byte, n), b[:n])), n
+	}
+
+	raw, n := unescape(invert(make([]byte, len(b)), b))
+	return d.Key.Restore(raw), n
+}
+
+var _ BinaryComparableKey[int64] = Desc[int64]{}
+
+// invert stores the bitwise complement of src in dst and returns dst.
+func invert(dst, src []byte) []byte {
+	for i, c := range src {
+		dst[i] = ^c
+	}
+	return dst
+}
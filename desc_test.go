@@ -0,0 +1,157 @@
+package art_test
+
+import (
+	"math"
+	"slices"
+	"testing"
+
+	"github.com/Clement-Jean/go-art"
+)
+
+type Event struct {
+	Tenant string
+	At     int64
+}
+
+// EventKey orders the events by tenant and then from the latest to the
+// oldest. An event without At encodes only the tenant, to be used with
+// Prefix.
+type EventKey struct{}
+
+func (ek EventKey) Transform(e Event) ([]byte, []byte) {
+	var (
+		ebk  art.EscapedBinaryKey[string]
+		desc = art.Desc[int64]{Key: art.SignedBinaryKey[int64]{}}
+	)
+
+	_, b := ebk.Transform(e.Tenant)
+	if e.At != 0 {
+		_, at := desc.Transform(e.At)
+		b = append(b, at...)
+	}
+	return b, b
+}
+func (ek EventKey) Restore(b []byte) Event {
+	var (
+		ebk  art.EscapedBinaryKey[string]
+		desc = art.Desc[int64]{Key: art.SignedBinaryKey[int64]{}}
+	)
+
+	// the tenant is followed by the 8 bytes of At
+	tenant := ebk.Restore(b[:len(b)-8])
+	at, _ := desc.RestorePrefix(b[len(b)-8:])
+	return Event{Tenant: tenant, At: at}
+}
+
+func TestDescCompound(t *testing.T) {
+	tr := art.NewCompoundTree[Event, int](EventKey{})
+
+	events := []Event{
+		{"acme", 10}, {"acme", 30}, {"acme", 20},
+		{"ac", 5}, {"acmex", 40}, {"globex", 1}, {"acme", -1},
+	}
+	for i, e := range events {
+		tr.Insert(e, i)
+	}
+
+	var got []Event
+	for k, _ := range tr.Prefix(Event{Tenant: "acme"}) {
+		got = append(got, k)
+	}
+
+	expected := []Event{{"acme", 30}, {"acme", 20}, {"acme", 10}, {"acme", -1}}
+	if !slices.Equal(expected, got) {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+
+	got = got[:0]
+	for k, _ := range tr.All() {
+		got = append(got, k)
+	}
+
+	expected = []Event{
+		{"ac", 5}, {"acme", 30}, {"acme", 20}, {"acme", 10}, {"acme", -1},
+		{"acmex", 40}, {"globex", 1},
+	}
+	if !slices.Equal(expected, got) {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+}
+
+func TestDescVariableLength(t *testing.T) {
+	desc := art.Desc[string]{Key: art.AlphabeticalOrderKey[string]{}}
+	tr := art.NewCompoundTree[string, int](desc)
+
+	keys := []string{"", "a", "a\x00", "ab", "abc", "b", "\x00"}
+	for i, k := range keys {
+		tr.Insert(k, i)
+	}
+
+	var got []string
+	for k, _ := range tr.All() {
+		got = append(got, k)
+	}
+
+	expected := []string{"b", "abc", "ab", "a\x00", "a", "\x00", ""}
+	if !slices.Equal(expected, got) {
+		t.Fatalf("expected %q, got %q", expected, got)
+	}
+}
+
+func TestDescendingTrees(t *testing.T) {
+	t.Run("unsigned", func(t *testing.T) {
+		tr := art.NewUnsignedBinaryTree[uint16, int](art.WithDescending())
+		for i, k := range []uint16{3, 0, math.MaxUint16, 256} {
+			tr.Insert(k, i)
+		}
+
+		var got []uint16
+		for k, _ := range tr.All() {
+			got = append(got, k)
+		}
+
+		if expected := []uint16{math.MaxUint16, 256, 3, 0}; !slices.Equal(expected, got) {
+			t.Fatalf("expected %v, got %v", expected, got)
+		}
+
+		if k, _, _ := tr.Minimum(); k != math.MaxUint16 {
+			t.Fatalf("expected minimum %d, got %d", uint16(math.MaxUint16), k)
+		}
+	})
+
+	t.Run("signed", func(t *testing.T) {
+		tr := art.NewSignedBinaryTree[int64, int](art.WithDescending())
+		for i, k := range []int64{-5, 7, 0, math.MinInt64, 12} {
+			tr.Insert(k, i)
+		}
+
+		var got []int64
+		for k, _ := range tr.Range(10, -5) {
+			got = append(got, k)
+		}
+
+		if expected := []int64{7, 0, -5}; !slices.Equal(expected, got) {
+			t.Fatalf("expected %v, got %v", expected, got)
+		}
+
+		if _, ok := tr.Search(math.MinInt64); !ok {
+			t.Fatalf("expected %d to be found", int64(math.MinInt64))
+		}
+	})
+
+	t.Run("float", func(t *testing.T) {
+		tr := art.NewFloatBinaryTree[float64, int](art.WithDescending())
+		for i, k := range []float64{1.5, -2, math.Inf(1), 0, math.Inf(-1)} {
+			tr.Insert(k, i)
+		}
+
+		var got []float64
+		for k, _ := range tr.All() {
+			got = append(got, k)
+		}
+
+		if expected := []float64{math.Inf(1), 1.5, 0, -2, math.Inf(-1)}; !slices.Equal(expected, got) {
+			t.Fatalf("expected %v, got %v", expected, got)
+		}
+	})
+}
@@ -209,4 +209,6 @@ func main() {
 	floatTree()
 	println()
 	compoundTree()
+	println()
+	orderTree()
 }
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/Clement-Jean/go-art"
+)
+
+//go:generate go run ../cmd/go-art -type=Order
+
+type Order struct {
+	Customer string `art:"1"`
+	Placed   int64  `art:"2,desc"`
+	Total    float64
+}
+
+func orderTree() {
+	// OrderKey is generated, art.KeyOf[Order]() is the same key using reflection
+	tree := art.NewCompoundTree[Order, float64](OrderKey{})
+
+	for _, o := range []Order{
+		{Customer: "bob", Placed: 1, Total: 9.5},
+		{Customer: "alice", Placed: 2, Total: 12},
+		{Customer: "alice", Placed: 3, Total: 3.25},
+	} {
+		tree.Insert(o, o.Total)
+	}
+
+	fmt.Println("Latest orders first:")
+	for key, value := range tree.All() {
+		fmt.Printf("Key: %s/%d, Value: %.2f\n", key.Customer, key.Placed, value)
+	}
+}
@@ -0,0 +1,29 @@
+// Code generated by "go-art -type=Order"; DO NOT EDIT.
+
+package main
+
+import "github.com/Clement-Jean/go-art"
+
+// OrderKey orders Order by the fields tagged with art.
+type OrderKey struct{}
+
+func (OrderKey) Transform(k Order) ([]byte, []byte) {
+	var b []byte
+	_, f0 := art.EscapedBinaryKey[string]{}.Transform(k.Customer)
+	b = append(b, f0...)
+	_, f1 := art.Desc[int64]{Key: art.SignedBinaryKey[int64]{}}.Transform(k.Placed)
+	b = append(b, f1...)
+	return b, b
+}
+
+func (OrderKey) Restore(b []byte) Order {
+	var (
+		k Order
+		n int
+	)
+	k.Customer, n = art.EscapedBinaryKey[string]{}.RestorePrefix(b)
+	b = b[n:]
+	k.Placed, n = art.Desc[int64]{Key: art.SignedBinaryKey[int64]{}}.RestorePrefix(b)
+	b = b[n:]
+	return k
+}
@@ -1,5 +1,46 @@
 package art
 
-func NewFloatBinaryTree[K floats, V any]() Tree[K, V] {
-	return &floatSortedTree[K, V]{}
+func NewFloatBinaryTree[K floats, V any](opts ...Option) Tree[K, V] {
+	return &floatSortedTree[K, V]{
+		floatCodec: floatCodec[K]{opts: newTreeOptions(treeOptions{}, opts)},
+	}
+}
+
+// NewFloatBinarySet returns a set ordering the keys like
+// NewFloatBinaryTree.
+func NewFloatBinarySet[K floats](opts ...Option) Set[K] {
+	t := &floatSortedTree[K, struct{}]{
+		floatCodec: floatCodec[K]{opts: newTreeOptions(treeOptions{}, opts)},
+	}
+	return &keySet[K]{inner: t, encode: t.encodeKey}
+}
+
+// floatCodec encodes the keys of the float trees.
+type floatCodec[K floats] struct {
+	bck  FloatBinaryKey[K]
+	opts treeOptions
+}
+
+func (c *floatCodec[K]) encodeKey(key K) []byte {
+	var keyS []byte
+	if c.opts.totalOrder {
+		_, keyS = TotalOrderFloatKey[K]{}.Transform(key)
+	} else {
+		_, keyS = c.bck.Transform(key)
+	}
+	if c.opts.descending {
+		invert(keyS, keyS)
+	}
+	return keyS
+}
+
+func (c *floatCodec[K]) decodeKey(b []byte) K {
+	if c.opts.descending {
+		var buf [8]byte
+		b = invert(buf[:len(b)], b)
+	}
+	if c.opts.totalOrder {
+		return TotalOrderFloatKey[K]{}.Restore(b)
+	}
+	return c.bck.Restore(b)
 }
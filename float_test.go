@@ -197,3 +197,54 @@ func TestFloatRange(t *testing.T) {
 		})
 	}
 }
+
+func TestFloatTotalOrder(t *testing.T) {
+	negNaN := math.Float64frombits(0xfff8000000000001)
+	posNaN := math.Float64frombits(0x7ff8000000000001)
+	otherNaN := math.Float64frombits(0x7ff8000000000002)
+	negZero := math.Copysign(0, -1)
+
+	keys := []float64{
+		negNaN, math.Inf(-1), -1.5, -math.SmallestNonzeroFloat64, negZero,
+		0, math.SmallestNonzeroFloat64, 2, math.Inf(1), posNaN, otherNaN,
+	}
+
+	tr := art.NewFloatBinaryTree[float64, int](art.WithTotalOrder())
+	for i := len(keys) - 1; i >= 0; i-- {
+		tr.Insert(keys[i], i)
+	}
+
+	if tr.Size() != len(keys) {
+		t.Fatalf("expected %d distinct keys, got %d", len(keys), tr.Size())
+	}
+
+	i := 0
+	for k, v := range tr.All() {
+		if math.Float64bits(k) != math.Float64bits(keys[i]) || v != i {
+			t.Fatalf("expected %x at %d, got %x", math.Float64bits(keys[i]), i, math.Float64bits(k))
+		}
+		i++
+	}
+
+	if v, ok := tr.Search(negZero); !ok || v != 4 {
+		t.Fatalf("expected -0 to be found with 4, got %d", v)
+	}
+}
+
+func TestFloat32TotalOrder(t *testing.T) {
+	nan := math.Float32frombits(0x7fc00123)
+	keys := []float32{float32(math.Inf(-1)), -1, float32(math.Copysign(0, -1)), 0, 1, nan}
+
+	tr := art.NewFloatBinaryTree[float32, int](art.WithTotalOrder(), art.WithDescending())
+	for i, k := range keys {
+		tr.Insert(k, i)
+	}
+
+	i := len(keys) - 1
+	for k, _ := range tr.All() {
+		if math.Float32bits(k) != math.Float32bits(keys[i]) {
+			t.Fatalf("expected %x, got %x", math.Float32bits(keys[i]), math.Float32bits(k))
+		}
+		i--
+	}
+}
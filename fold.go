@@ -0,0 +1,162 @@
+package art
+
+import (
+	"bytes"
+	"iter"
+	"unicode"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/unicode/norm"
+)
+
+type caseFolding uint8
+
+const (
+	noCaseFolding caseFolding = iota
+	simpleCaseFolding
+	fullCaseFolding
+)
+
+var fullFolder = cases.Fold()
+
+// WithNormalization makes NewAlphaSortedTree compare the keys in the given
+// Unicode normalization form, usually norm.NFC or norm.NFKC, so that
+// canonically equivalent spellings are the same key.
+func WithNormalization(form norm.Form) Option {
+	return func(o *treeOptions) {
+		o.normalize = true
+		o.form = form
+	}
+}
+
+// WithSimpleCaseFolding makes NewAlphaSortedTree compare the keys after
+// mapping each rune to a single case, as strings.EqualFold does.
+func WithSimpleCaseFolding() Option {
+	return func(o *treeOptions) {
+		o.fold = simpleCaseFolding
+	}
+}
+
+// WithFullCaseFolding makes NewAlphaSortedTree compare the keys after full
+// Unicode case folding, which also maps runes to several runes, e.g. "ß" to
+// "ss".
+func WithFullCaseFolding() Option {
+	return func(o *treeOptions) {
+		o.fold = fullCaseFolding
+	}
+}
+
+// foldsText reports whether the keys are folded before being stored.
+func (o *treeOptions) foldsText() bool {
+	return o.normalize || o.fold != noCaseFolding
+}
+
+// foldText case folds and then normalizes b.
+func (o *treeOptions) foldText(b []byte) []byte {
+	switch o.fold {
+	case simpleCaseFolding:
+		b = bytes.Map(simpleFold, b)
+	case fullCaseFolding:
+		b = fullFolder.Bytes(b)
+	}
+
+	if o.normalize {
+		b = o.form.Bytes(b)
+	}
+	return b
+}
+
+// simpleFold maps r to the same rune as the other runes of its
+// unicode.SimpleFold orbit, which are the runes strings.EqualFold considers
+// equal: the lower case of the smallest rune of the orbit when it's in the
+// orbit, e.g. 'k' for the Kelvin sign, and the smallest rune otherwise, e.g.
+// 'ı' stays apart from 'i' and 'I'.
+func simpleFold(r rune) rune {
+	m := r
+	for f := unicode.SimpleFold(r); f != r; f = unicode.SimpleFold(f) {
+		m = min(m, f)
+	}
+
+	lower := unicode.ToLower(m)
+	for f := unicode.SimpleFold(m); f != m; f = unicode.SimpleFold(f) {
+		if f == lower {
+			return lower
+		}
+	}
+	return m
+}
+
+// spelling is the value stored by a spellingTree.
+type spelling[K chars, V any] struct {
+	key   K
+	value V
+}
+
+// spellingTree wraps an alpha tree folding its keys and keeps the spelling
+// of the keys in the values, so that the iterations return the keys as they
+// were inserted. Keys with the same folding are the same key and the last
+// inserted spelling is kept.
+type spellingTree[K chars, V any] struct {
+	inner   Tree[K, spelling[K, V]]
+	keyCopy bool
+}
+
+func (t *spellingTree[K, V]) Insert(key K, val V) {
+	if b, ok := any(key).([]byte); ok && t.keyCopy {
+		key = K(bytes.Clone(b))
+	}
+	t.inner.Insert(key, spelling[K, V]{key: key, value: val})
+}
+
+func (t *spellingTree[K, V]) Search(key K) (V, bool) {
+	s, ok := t.inner.Search(key)
+	return s.value, ok
+}
+
+func (t *spellingTree[K, V]) Delete(key K) bool { return t.inner.Delete(key) }
+
+func (t *spellingTree[K, V]) Minimum() (K, V, bool) {
+	_, s, ok := t.inner.Minimum()
+	return s.key, s.value, ok
+}
+
+func (t *spellingTree[K, V]) Maximum() (K, V, bool) {
+	_, s, ok := t.inner.Maximum()
+	return s.key, s.value, ok
+}
+
+func (t *spellingTree[K, V]) All() iter.Seq2[K, V] { return spelled(t.inner.All()) }
+
+func (t *spellingTree[K, V]) Backward() iter.Seq2[K, V] { return spelled(t.inner.Backward()) }
+
+func (t *spellingTree[K, V]) Prefix(p K) iter.Seq2[K, V] { return spelled(t.inner.Prefix(p)) }
+
+func (t *spellingTree[K, V]) PrefixBackward(p K) iter.Seq2[K, V] {
+	return spelled(t.inner.PrefixBackward(p))
+}
+
+func (t *spellingTree[K, V]) TopK(k uint) iter.Seq2[K, V] { return spelled(t.inner.TopK(k)) }
+
+func (t *spellingTree[K, V]) BottomK(k uint) iter.Seq2[K, V] { return spelled(t.inner.BottomK(k)) }
+
+func (t *spellingTree[K, V]) Range(start, end K) iter.Seq2[K, V] {
+	return spelled(t.inner.Range(start, end))
+}
+
+func (t *spellingTree[K, V]) RangeWith(opts RangeOptions[K]) iter.Seq2[K, V] {
+	return spelled(t.inner.RangeWith(opts))
+}
+
+func (t *spellingTree[K, V]) Size() int { return t.inner.Size() }
+
+func (t *spellingTree[K, V]) Stats() Stats { return t.inner.Stats() }
+
+func spelled[K chars, V any](seq iter.Seq2[K, spelling[K, V]]) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for _, s := range seq {
+			if !yield(s.key, s.value) {
+				return
+			}
+		}
+	}
+}
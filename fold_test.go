@@ -0,0 +1,126 @@
+package art_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/Clement-Jean/go-art"
+	"golang.org/x/text/unicode/norm"
+)
+
+func TestAlphaNormalization(t *testing.T) {
+	tr := art.NewAlphaSortedTree[string, int](art.WithNormalization(norm.NFC))
+
+	tr.Insert("café", 1)  // NFC
+	tr.Insert("café", 2) // NFD
+
+	if tr.Size() != 1 {
+		t.Fatalf("expected both spellings to be the same key, got size %d", tr.Size())
+	}
+
+	if v, ok := tr.Search("café"); !ok || v != 2 {
+		t.Fatalf("expected 2, got %d", v)
+	}
+
+	k, _, _ := tr.Minimum()
+	if k != "café" {
+		t.Fatalf("expected the last spelling %q, got %q", "café", k)
+	}
+}
+
+func TestAlphaNormalizationNFKC(t *testing.T) {
+	tr := art.NewAlphaSortedTree[string, int](art.WithNormalization(norm.NFKC))
+
+	tr.Insert("ﬁle", 1) // "fi" ligature
+
+	if _, ok := tr.Search("file"); !ok {
+		t.Fatal("expected the ligature to match file")
+	}
+}
+
+func TestAlphaCaseFolding(t *testing.T) {
+	tests := []struct {
+		name     string
+		opt      art.Option
+		keys     []string
+		search   string
+		expected []string
+	}{
+		{
+			name:     "simple",
+			opt:      art.WithSimpleCaseFolding(),
+			keys:     []string{"Banana", "apple", "Cherry", "APPLE-pie"},
+			search:   "BANANA",
+			expected: []string{"apple", "APPLE-pie", "Banana", "Cherry"},
+		},
+		{
+			name:     "simple kelvin",
+			opt:      art.WithSimpleCaseFolding(),
+			keys:     []string{"K", "j"}, // Kelvin sign
+			search:   "k",
+			expected: []string{"j", "K"},
+		},
+		{
+			name:     "simple dotless i",
+			opt:      art.WithSimpleCaseFolding(),
+			keys:     []string{"\u0131", "I", "\u0130"}, // ı, I and İ, as strings.EqualFold
+			search:   "i",
+			expected: []string{"I", "\u0130", "\u0131"},
+		},
+		{
+			name:     "full",
+			opt:      art.WithFullCaseFolding(),
+			keys:     []string{"Straße", "strasse-2", "STRAND"},
+			search:   "STRASSE",
+			expected: []string{"STRAND", "Straße", "strasse-2"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tr := art.NewAlphaSortedTree[string, int](tt.opt)
+			for i, k := range tt.keys {
+				tr.Insert(k, i)
+			}
+
+			if _, ok := tr.Search(tt.search); !ok {
+				t.Fatalf("expected %q to be found", tt.search)
+			}
+
+			var got []string
+			for k, _ := range tr.All() {
+				got = append(got, k)
+			}
+
+			if !slices.Equal(tt.expected, got) {
+				t.Fatalf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestAlphaCaseFoldingPrefix(t *testing.T) {
+	tr := art.NewAlphaSortedTree[[]byte, int](art.WithSimpleCaseFolding(), art.WithNormalization(norm.NFC))
+
+	buf := []byte("HTTPServer")
+	tr.Insert(buf, 1)
+	copy(buf, "xxxx")
+	tr.Insert([]byte("httpClient"), 2)
+	tr.Insert([]byte("Hyper"), 3)
+
+	var got []string
+	for k, _ := range tr.Prefix([]byte("Http")) {
+		got = append(got, string(k))
+	}
+
+	if expected := []string{"httpClient", "HTTPServer"}; !slices.Equal(expected, got) {
+		t.Fatalf("expected %q, got %q", expected, got)
+	}
+
+	if !tr.Delete([]byte("httpserver")) {
+		t.Fatal("expected httpserver to be deleted")
+	}
+	if tr.Size() != 2 {
+		t.Fatalf("expected size 2, got %d", tr.Size())
+	}
+}
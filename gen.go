@@ -1,4 +1,4 @@
 package art
 
-//go:generate go run cmd/go-art/main.go
+//go:generate go run ./cmd/go-art
 //go:generate gofmt -w trees.go
@@ -0,0 +1,220 @@
+package art
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"unsafe"
+)
+
+// KeyOf derives a BinaryComparableKey from the fields of the struct T tagged
+// with their position in the key, optionally followed by desc to reverse
+// their order:
+//
+//	type Account struct {
+//		Tenant string `art:"1"`
+//		ID     uint64 `art:"2,desc"`
+//	}
+//
+// The supported fields are the integers, the floats, the strings and the
+// byte slices. They are encoded like UnsignedBinaryKey, SignedBinaryKey,
+// FloatBinaryKey and EscapedBinaryKey, and like Desc when reversed. The
+// untagged fields are left empty by Restore.
+//
+// KeyOf panics if T isn't a struct, if a tag is invalid or if a tagged field
+// isn't supported. Running cmd/go-art with -type generates the same key
+// without reflection.
+func KeyOf[T any]() BinaryComparableKey[T] {
+	typ := reflect.TypeFor[T]()
+	if typ.Kind() != reflect.Struct {
+		panic(fmt.Sprintf("art: KeyOf needs a struct, got %s", typ))
+	}
+
+	var k structKey[T]
+	for i := range typ.NumField() {
+		f := typ.Field(i)
+		tag, ok := f.Tag.Lookup("art")
+		if !ok {
+			continue
+		}
+
+		pos, desc, err := parseKeyTag(tag)
+		if err != nil {
+			panic(fmt.Sprintf("art: field %s.%s: %v", typ, f.Name, err))
+		}
+
+		c, ok := fieldCodecs[f.Type.Kind()]
+		if !ok || (f.Type.Kind() == reflect.Slice && f.Type.Elem().Kind() != reflect.Uint8) {
+			panic(fmt.Sprintf("art: field %s.%s: unsupported type %s", typ, f.Name, f.Type))
+		}
+
+		c.index, c.pos, c.desc = i, pos, desc
+		if c.fixed {
+			c.size = int(f.Type.Size())
+		}
+		k.fields = append(k.fields, c)
+	}
+
+	slices.SortFunc(k.fields, func(a, b fieldCodec) int { return a.pos - b.pos })
+	for i := 1; i < len(k.fields); i++ {
+		if k.fields[i-1].pos == k.fields[i].pos {
+			panic(fmt.Sprintf("art: %s has two fields at position %d", typ, k.fields[i].pos))
+		}
+	}
+	return k
+}
+
+// parseKeyTag parses tags such as "1" or "2,desc".
+func parseKeyTag(tag string) (pos int, desc bool, err error) {
+	p, opt, _ := strings.Cut(tag, ",")
+
+	if pos, err = strconv.Atoi(p); err != nil {
+		return 0, false, fmt.Errorf("invalid position %q", p)
+	}
+
+	switch opt {
+	case "":
+	case "desc":
+		desc = true
+	default:
+		return 0, false, fmt.Errorf("unknown option %q", opt)
+	}
+	return pos, desc, nil
+}
+
+// fieldCodec encodes a field. The variable-length encodings are escaped and
+// terminated by structKey.
+type fieldCodec struct {
+	index, pos int
+	desc       bool
+
+	fixed bool
+	size  int
+
+	encode func(reflect.Value) []byte
+	decode func([]byte, reflect.Value)
+}
+
+var fieldCodecs = map[reflect.Kind]fieldCodec{}
+
+func init() {
+	unsigned := fieldCodec{
+		fixed: true,
+		encode: func(v reflect.Value) []byte {
+			b := binary.BigEndian.AppendUint64(nil, v.Uint())
+			return b[8-v.Type().Size():]
+		},
+		decode: func(b []byte, v reflect.Value) {
+			var u [8]byte
+			copy(u[8-len(b):], b)
+			v.SetUint(binary.BigEndian.Uint64(u[:]))
+		},
+	}
+	signed := fieldCodec{
+		fixed: true,
+		encode: func(v reflect.Value) []byte {
+			b := binary.BigEndian.AppendUint64(nil, uint64(v.Int()))
+			b = b[8-v.Type().Size():]
+			b[0] ^= 0x80
+			return b
+		},
+		decode: func(b []byte, v reflect.Value) {
+			var u [8]byte
+			copy(u[8-len(b):], b)
+			u[8-len(b)] ^= 0x80
+			shift := 64 - 8*len(b)
+			v.SetInt(int64(binary.BigEndian.Uint64(u[:])<<shift) >> shift)
+		},
+	}
+
+	for _, kind := range []reflect.Kind{reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64} {
+		fieldCodecs[kind] = unsigned
+	}
+	for _, kind := range []reflect.Kind{reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64} {
+		fieldCodecs[kind] = signed
+	}
+
+	fieldCodecs[reflect.Float32] = fieldCodec{
+		fixed: true,
+		encode: func(v reflect.Value) []byte {
+			_, b := FloatBinaryKey[float32]{}.Transform(float32(v.Float()))
+			return b
+		},
+		decode: func(b []byte, v reflect.Value) {
+			v.SetFloat(float64(FloatBinaryKey[float32]{}.Restore(b)))
+		},
+	}
+	fieldCodecs[reflect.Float64] = fieldCodec{
+		fixed: true,
+		encode: func(v reflect.Value) []byte {
+			_, b := FloatBinaryKey[float64]{}.Transform(v.Float())
+			return b
+		},
+		decode: func(b []byte, v reflect.Value) {
+			v.SetFloat(FloatBinaryKey[float64]{}.Restore(b))
+		},
+	}
+	fieldCodecs[reflect.String] = fieldCodec{
+		encode: func(v reflect.Value) []byte { return []byte(v.String()) },
+		decode: func(b []byte, v reflect.Value) { v.SetString(string(b)) },
+	}
+	fieldCodecs[reflect.Slice] = fieldCodec{
+		encode: func(v reflect.Value) []byte { return v.Bytes() },
+		decode: func(b []byte, v reflect.Value) { v.SetBytes(bytes.Clone(b)) },
+	}
+}
+
+// structKey is the key returned by KeyOf.
+type structKey[T any] struct {
+	fields []fieldCodec
+}
+
+func (sk structKey[T]) Transform(k T) ([]byte, []byte) {
+	var b []byte
+
+	v := reflect.ValueOf(&k).Elem()
+	for _, f := range sk.fields {
+		start := len(b)
+		if f.fixed {
+			b = append(b, f.encode(v.Field(f.index))...)
+		} else {
+			b = appendEscaped(b, f.encode(v.Field(f.index)))
+			b = append(b, escapeByte, endByte)
+		}
+
+		if f.desc {
+			invert(b[start:], b[start:])
+		}
+	}
+	return b, b
+}
+func (sk structKey[T]) Restore(b []byte) T {
+	var k T
+
+	v := reflect.ValueOf(&k).Elem()
+	for _, f := range sk.fields {
+		enc := b
+		if f.desc {
+			enc = invert(make([]byte, len(b)), b)
+		}
+
+		var raw []byte
+		n := f.size
+		if f.fixed {
+			raw = enc[:n]
+		} else {
+			raw, n = unescape(enc)
+		}
+
+		// unexported fields aren't settable through reflection
+		field := v.Field(f.index)
+		field = reflect.NewAt(field.Type(), unsafe.Pointer(field.UnsafeAddr())).Elem()
+		f.decode(raw, field)
+		b = b[n:]
+	}
+	return k
+}
@@ -0,0 +1,120 @@
+package art_test
+
+import (
+	"bytes"
+	"cmp"
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	"github.com/Clement-Jean/go-art"
+)
+
+type taggedAccount struct {
+	tenant  string  `art:"1"`
+	created int32   `art:"2,desc"`
+	id      uint16  `art:"3"`
+	score   float64 `art:"4,desc"`
+	blob    []byte  `art:"5"`
+	note    string
+}
+
+func compareTagged(a, b taggedAccount) int {
+	return cmp.Or(
+		cmp.Compare(a.tenant, b.tenant),
+		-cmp.Compare(a.created, b.created),
+		cmp.Compare(a.id, b.id),
+		-cmp.Compare(a.score, b.score),
+		bytes.Compare(a.blob, b.blob),
+	)
+}
+
+func TestKeyOf(t *testing.T) {
+	r := rand.New(rand.NewPCG(11, 12))
+	tr := art.NewCompoundTree[taggedAccount, int](art.KeyOf[taggedAccount]())
+
+	var accounts []taggedAccount
+	for i := range 5_000 {
+		a := taggedAccount{
+			tenant:  []string{"", "a", "a\x00", "ab", "b"}[r.IntN(5)],
+			created: r.Int32N(20) - 10,
+			id:      uint16(r.IntN(3)) * 300,
+			score:   float64(r.IntN(5)) - 2.5,
+			blob:    [][]byte{nil, {0}, {0, 1}, {1}}[r.IntN(4)],
+			note:    "ignored",
+		}
+		tr.Insert(a, i)
+		accounts = append(accounts, a)
+	}
+
+	slices.SortFunc(accounts, compareTagged)
+	accounts = slices.CompactFunc(accounts, func(a, b taggedAccount) bool { return compareTagged(a, b) == 0 })
+
+	var got []taggedAccount
+	for k, _ := range tr.All() {
+		if k.note != "" {
+			t.Fatalf("expected the untagged field to be empty, got %q", k.note)
+		}
+		got = append(got, k)
+	}
+
+	if !slices.EqualFunc(accounts, got, func(a, b taggedAccount) bool { return compareTagged(a, b) == 0 }) {
+		t.Fatalf("expected %d sorted accounts, got %d", len(accounts), len(got))
+	}
+}
+
+type order struct {
+	Customer string `art:"1"`
+	Placed   int64  `art:"2,desc"`
+}
+
+func TestKeyOfEncoders(t *testing.T) {
+	o := order{Customer: "al\x00ice", Placed: -42}
+
+	var (
+		ebk  art.EscapedBinaryKey[string]
+		desc = art.Desc[int64]{Key: art.SignedBinaryKey[int64]{}}
+	)
+	_, customer := ebk.Transform(o.Customer)
+	_, placed := desc.Transform(o.Placed)
+	expected := append(customer, placed...)
+
+	key := art.KeyOf[order]()
+	got, _ := key.Transform(o)
+
+	if !bytes.Equal(expected, got) {
+		t.Fatalf("expected %x, got %x", expected, got)
+	}
+	if key.Restore(got) != o {
+		t.Fatalf("expected %v, got %v", o, key.Restore(got))
+	}
+}
+
+func TestKeyOfInvalid(t *testing.T) {
+	type notKey struct {
+		A map[string]int `art:"1"`
+	}
+	type badTag struct {
+		A int `art:"first"`
+	}
+	type samePos struct {
+		A int `art:"1"`
+		B int `art:"1"`
+	}
+
+	for name, f := range map[string]func(){
+		"not a struct": func() { art.KeyOf[int]() },
+		"unsupported":  func() { art.KeyOf[notKey]() },
+		"bad tag":      func() { art.KeyOf[badTag]() },
+		"same pos":     func() { art.KeyOf[samePos]() },
+	} {
+		t.Run(name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Fatal("expected a panic")
+				}
+			}()
+			f()
+		})
+	}
+}
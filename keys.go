@@ -1,6 +1,7 @@
 package art
 
 import (
+	"bytes"
 	"encoding/binary"
 	"math"
 	"math/bits"
@@ -24,6 +25,70 @@ func (aok AlphabeticalOrderKey[K]) Restore(b []byte) K { return K(b) }
 
 var _ BinaryComparableKey[[]byte] = AlphabeticalOrderKey[[]byte]{}
 
+// EscapedBinaryKey orders arbitrary binary keys, including keys containing
+// 0x00 bytes. Each 0x00 is escaped as 0x00 0xFF and the key ends with 0x00
+// 0x01, which keeps the byte order and makes the keys prefix-free.
+type EscapedBinaryKey[K chars] struct{}
+
+func (ebk EscapedBinaryKey[K]) Transform(k K) ([]byte, []byte) {
+	b := appendEscaped(make([]byte, 0, len(k)+2), []byte(k))
+	b = append(b, escapeByte, endByte)
+	return b, b
+}
+func (ebk EscapedBinaryKey[K]) Restore(b []byte) K {
+	k, _ := ebk.RestorePrefix(b)
+	return k
+}
+
+// RestorePrefix restores the key encoded at the start of b and returns it
+// with the number of bytes it took.
+func (ebk EscapedBinaryKey[K]) RestorePrefix(b []byte) (K, int) {
+	k, n := unescape(b)
+	return K(k), n
+}
+
+var _ BinaryComparableKey[[]byte] = EscapedBinaryKey[[]byte]{}
+
+const (
+	escapeByte  = 0x00
+	escapedByte = 0xFF
+	endByte     = 0x01
+)
+
+// appendEscaped appends b to dst with every 0x00 replaced by 0x00 0xFF.
+func appendEscaped(dst, b []byte) []byte {
+	for {
+		i := bytes.IndexByte(b, escapeByte)
+		if i == -1 {
+			return append(dst, b...)
+		}
+
+		dst = append(dst, b[:i+1]...)
+		dst = append(dst, escapedByte)
+		b = b[i+1:]
+	}
+}
+
+// unescape decodes an escaped value up to its 0x00 0x01 end and returns it
+// with the number of bytes it took in b. A value without end takes all of b.
+func unescape(b []byte) ([]byte, int) {
+	var dst []byte
+
+	for i := 0; i < len(b); i++ {
+		if b[i] != escapeByte {
+			dst = append(dst, b[i])
+			continue
+		}
+
+		if i+1 == len(b) || b[i+1] == endByte {
+			return dst, min(i+2, len(b))
+		}
+		dst = append(dst, escapeByte)
+		i++ // skip escapedByte
+	}
+	return dst, len(b)
+}
+
 type CollationOrderKey[K chars | []rune] struct {
 	c   *collate.Collator
 	buf *collate.Buffer
@@ -39,6 +104,27 @@ func (cok *CollationOrderKey[K]) Restore(b []byte) K { return cok.src }
 
 var _ BinaryComparableKey[[]rune] = &CollationOrderKey[[]rune]{}
 
+type fixedBytes interface {
+	~[4]byte | ~[6]byte | ~[8]byte | ~[12]byte | ~[16]byte | ~[20]byte | ~[32]byte | ~[64]byte
+}
+
+// FixedBytesKey orders byte arrays such as UUIDs, hashes or IP addresses.
+// All the keys have the same length and are stored as is, without
+// terminator.
+type FixedBytesKey[K fixedBytes] struct{}
+
+func (fbk FixedBytesKey[K]) Transform(k K) ([]byte, []byte) {
+	b := unsafe.Slice((*byte)(unsafe.Pointer(&k)), unsafe.Sizeof(k))
+	return b, b
+}
+func (fbk FixedBytesKey[K]) Restore(b []byte) K {
+	var k K
+	copy(unsafe.Slice((*byte)(unsafe.Pointer(&k)), unsafe.Sizeof(k)), b)
+	return k
+}
+
+var _ BinaryComparableKey[[16]byte] = FixedBytesKey[[16]byte]{}
+
 type UnsignedBinaryKey[K uints] struct{}
 
 func (ubk UnsignedBinaryKey[K]) Transform(k K) ([]byte, []byte) {
@@ -91,6 +177,13 @@ func (ubk UnsignedBinaryKey[K]) Restore(b []byte) K {
 	}
 }
 
+// RestorePrefix restores the key encoded at the start of b and returns it
+// with the number of bytes it took.
+func (ubk UnsignedBinaryKey[K]) RestorePrefix(b []byte) (K, int) {
+	n := int(unsafe.Sizeof(K(0)))
+	return ubk.Restore(b[:n]), n
+}
+
 var _ BinaryComparableKey[uint] = UnsignedBinaryKey[uint]{}
 
 type SignedBinaryKey[K ints] struct{}
@@ -151,6 +244,13 @@ func (sbk SignedBinaryKey[K]) Restore(b []byte) K {
 	}
 }
 
+// RestorePrefix restores the key encoded at the start of b and returns it
+// with the number of bytes it took.
+func (sbk SignedBinaryKey[K]) RestorePrefix(b []byte) (K, int) {
+	n := int(unsafe.Sizeof(K(0)))
+	return sbk.Restore(b[:n]), n
+}
+
 var _ BinaryComparableKey[int] = SignedBinaryKey[int]{}
 
 type FloatBinaryKey[K floats] struct{}
@@ -256,4 +356,80 @@ func (fbk FloatBinaryKey[K]) Restore(b []byte) K {
 	}
 }
 
+// RestorePrefix restores the key encoded at the start of b and returns it
+// with the number of bytes it took.
+func (fbk FloatBinaryKey[K]) RestorePrefix(b []byte) (K, int) {
+	n := int(unsafe.Sizeof(K(0)))
+	return fbk.Restore(b[:n]), n
+}
+
 var _ BinaryComparableKey[float64] = FloatBinaryKey[float64]{}
+
+// TotalOrderFloatKey orders floats following the IEEE-754 totalOrder
+// predicate: -NaN < -Inf < ... < -0 < +0 < ... < +Inf < +NaN. Unlike
+// FloatBinaryKey, every float is a distinct key and restores bit for bit,
+// including negative zero and the NaN payloads.
+type TotalOrderFloatKey[K floats] struct{}
+
+func (tok TotalOrderFloatKey[K]) Transform(k K) ([]byte, []byte) {
+	var b []byte
+
+	switch any(k).(type) {
+	case float32:
+		i := *(*uint32)(unsafe.Pointer(&k))
+		if i&0x80000000 != 0 {
+			i = ^i
+		} else {
+			i ^= 0x80000000
+		}
+
+		b = make([]byte, 4)
+		binary.BigEndian.PutUint32(b, i)
+
+	case float64:
+		i := *(*uint64)(unsafe.Pointer(&k))
+		if i&0x8000000000000000 != 0 {
+			i = ^i
+		} else {
+			i ^= 0x8000000000000000
+		}
+
+		b = make([]byte, 8)
+		binary.BigEndian.PutUint64(b, i)
+	}
+	return b, b
+}
+func (tok TotalOrderFloatKey[K]) Restore(b []byte) K {
+	var k K
+
+	switch any(k).(type) {
+	case float32:
+		i := binary.BigEndian.Uint32(b)
+		if i&0x80000000 != 0 {
+			i ^= 0x80000000
+		} else {
+			i = ^i
+		}
+		return *(*K)(unsafe.Pointer(&i))
+
+	case float64:
+		i := binary.BigEndian.Uint64(b)
+		if i&0x8000000000000000 != 0 {
+			i ^= 0x8000000000000000
+		} else {
+			i = ^i
+		}
+		return *(*K)(unsafe.Pointer(&i))
+	default:
+		panic("shouldn't be possible!")
+	}
+}
+
+// RestorePrefix restores the key encoded at the start of b and returns it
+// with the number of bytes it took.
+func (tok TotalOrderFloatKey[K]) RestorePrefix(b []byte) (K, int) {
+	n := int(unsafe.Sizeof(K(0)))
+	return tok.Restore(b[:n]), n
+}
+
+var _ BinaryComparableKey[float64] = TotalOrderFloatKey[float64]{}
@@ -244,3 +244,16 @@ func TestFloatKeysFloatZeroFloat64(t *testing.T) {
 		t.Fatalf("expected 0, got %f", res)
 	}
 }
+
+func TestEscapedKeys(t *testing.T) {
+	var ebk EscapedBinaryKey[string]
+
+	for _, k := range []string{"", "\x00", "a\x00b", "\x00\x00\xff", "\x01"} {
+		tmp, _ := ebk.Transform(k)
+		res := ebk.Restore(tmp)
+
+		if res != k {
+			t.Fatalf("expected %q, got %q", k, res)
+		}
+	}
+}
@@ -0,0 +1,172 @@
+package art
+
+import (
+	"iter"
+	"slices"
+)
+
+// Multimap is a Tree mapping a key to several values. The iterations yield a
+// pair per value, the values of a key in insertion order, and the backward
+// iterations yield the pairs in the reverse order.
+type Multimap[K nodeKey, V comparable] interface {
+	Tree[K, V]
+
+	// InsertDup adds a value to the values of key. Insert replaces them.
+	InsertDup(key K, val V)
+
+	// Values iterates over the values of key in insertion order.
+	Values(key K) iter.Seq[V]
+
+	// DeleteValue deletes the first occurrence of val in the values of key
+	// and reports whether there was one. Delete deletes all the values.
+	DeleteValue(key K, val V) bool
+
+	// Count returns the number of values of key.
+	Count(key K) int
+}
+
+// NewMultimap returns a Multimap storing the values of each key in a list in
+// the given tree, e.g. NewAlphaSortedTree[string, []int](). Search returns
+// the first value of a key and Size the number of values.
+func NewMultimap[K nodeKey, V comparable](inner Tree[K, []V]) Multimap[K, V] {
+	m := &multimap[K, V]{inner: inner}
+	for _, vals := range inner.All() {
+		m.size += len(vals)
+	}
+	return m
+}
+
+type multimap[K nodeKey, V comparable] struct {
+	inner Tree[K, []V]
+	size  int
+}
+
+func (m *multimap[K, V]) Insert(key K, val V) {
+	vals, _ := m.inner.Search(key)
+	m.size += 1 - len(vals)
+	m.inner.Insert(key, []V{val})
+}
+
+func (m *multimap[K, V]) InsertDup(key K, val V) {
+	vals, _ := m.inner.Search(key)
+	m.size++
+	m.inner.Insert(key, append(vals, val))
+}
+
+func (m *multimap[K, V]) Search(key K) (V, bool) {
+	vals, ok := m.inner.Search(key)
+	if !ok {
+		var zero V
+		return zero, false
+	}
+	return vals[0], true
+}
+
+func (m *multimap[K, V]) Values(key K) iter.Seq[V] {
+	vals, _ := m.inner.Search(key)
+	return slices.Values(vals)
+}
+
+func (m *multimap[K, V]) Count(key K) int {
+	vals, _ := m.inner.Search(key)
+	return len(vals)
+}
+
+func (m *multimap[K, V]) Delete(key K) bool {
+	vals, ok := m.inner.Search(key)
+	if !ok {
+		return false
+	}
+
+	m.size -= len(vals)
+	return m.inner.Delete(key)
+}
+
+func (m *multimap[K, V]) DeleteValue(key K, val V) bool {
+	vals, _ := m.inner.Search(key)
+	i := slices.Index(vals, val)
+	if i < 0 {
+		return false
+	}
+
+	m.size--
+	if len(vals) == 1 {
+		return m.inner.Delete(key)
+	}
+	// the list is copied rather than shifted, as the callers of Values may
+	// still be iterating over it. Appending never overwrites what they see.
+	m.inner.Insert(key, slices.Concat(vals[:i:i], vals[i+1:]))
+	return true
+}
+
+func (m *multimap[K, V]) Minimum() (K, V, bool) {
+	k, vals, ok := m.inner.Minimum()
+	if !ok {
+		var zero V
+		return k, zero, false
+	}
+	return k, vals[0], true
+}
+
+func (m *multimap[K, V]) Maximum() (K, V, bool) {
+	k, vals, ok := m.inner.Maximum()
+	if !ok {
+		var zero V
+		return k, zero, false
+	}
+	return k, vals[len(vals)-1], true
+}
+
+func (m *multimap[K, V]) All() iter.Seq2[K, V] { return pairs(m.inner.All(), false, 0) }
+
+func (m *multimap[K, V]) Backward() iter.Seq2[K, V] { return pairs(m.inner.Backward(), true, 0) }
+
+func (m *multimap[K, V]) Prefix(p K) iter.Seq2[K, V] { return pairs(m.inner.Prefix(p), false, 0) }
+
+func (m *multimap[K, V]) PrefixBackward(p K) iter.Seq2[K, V] {
+	return pairs(m.inner.PrefixBackward(p), true, 0)
+}
+
+func (m *multimap[K, V]) TopK(k uint) iter.Seq2[K, V] { return topK(m, k) }
+
+func (m *multimap[K, V]) BottomK(k uint) iter.Seq2[K, V] { return bottomK(m, k) }
+
+func (m *multimap[K, V]) Range(start, end K) iter.Seq2[K, V] {
+	return pairs(m.inner.Range(start, end), false, 0)
+}
+
+// RangeWith iterates over the pairs within the bounds of the options, the
+// limit counting the pairs rather than the keys.
+func (m *multimap[K, V]) RangeWith(opts RangeOptions[K]) iter.Seq2[K, V] {
+	limit := opts.Limit
+	opts.Limit = 0
+	return pairs(m.inner.RangeWith(opts), opts.Reverse, limit)
+}
+
+func (m *multimap[K, V]) Size() int { return m.size }
+
+func (m *multimap[K, V]) Stats() Stats { return m.inner.Stats() }
+
+// pairs yields a pair per value of seq, the values of a key in reverse order
+// when backward is set, and stops after limit pairs when limit is positive.
+func pairs[K nodeKey, V any](seq iter.Seq2[K, []V], backward bool, limit int) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		n := 0
+		for k, vals := range seq {
+			for i := range vals {
+				if backward {
+					i = len(vals) - 1 - i
+				}
+
+				if !yield(k, vals[i]) {
+					return
+				}
+
+				n++
+				if n == limit {
+					return
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package art_test
+
+import (
+	"iter"
+	"slices"
+	"testing"
+
+	"github.com/Clement-Jean/go-art"
+)
+
+type pair struct {
+	key string
+	val int
+}
+
+func collectPairs(seq iter.Seq2[string, int]) []pair {
+	var got []pair
+	for k, v := range seq {
+		got = append(got, pair{k, v})
+	}
+	return got
+}
+
+func TestMultimap(t *testing.T) {
+	m := art.NewMultimap(art.NewAlphaSortedTree[string, []int]())
+
+	m.InsertDup("b", 1)
+	m.InsertDup("a", 2)
+	m.InsertDup("b", 3)
+	m.InsertDup("b", 1)
+	m.InsertDup("c", 4)
+
+	if m.Size() != 5 || m.Count("b") != 3 || m.Count("z") != 0 {
+		t.Fatalf("expected 5 values and 3 for b, got %d and %d", m.Size(), m.Count("b"))
+	}
+
+	if got := slices.Collect(m.Values("b")); !slices.Equal([]int{1, 3, 1}, got) {
+		t.Fatalf("expected [1 3 1], got %v", got)
+	}
+
+	expected := []pair{{"a", 2}, {"b", 1}, {"b", 3}, {"b", 1}, {"c", 4}}
+	if got := collectPairs(m.All()); !slices.Equal(expected, got) {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+
+	slices.Reverse(expected)
+	if got := collectPairs(m.Backward()); !slices.Equal(expected, got) {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+
+	if got := collectPairs(m.BottomK(2)); !slices.Equal([]pair{{"a", 2}, {"b", 1}}, got) {
+		t.Fatalf("expected the 2 first pairs, got %v", got)
+	}
+
+	got := collectPairs(m.RangeWith(art.RangeOptions[string]{
+		Start:   art.Inclusive("b"),
+		End:     art.Unbounded[string](),
+		Reverse: true,
+		Limit:   3,
+	}))
+	if expected := []pair{{"c", 4}, {"b", 1}, {"b", 3}}; !slices.Equal(expected, got) {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+}
+
+func TestMultimapDelete(t *testing.T) {
+	m := art.NewMultimap(art.NewUnsignedBinaryTree[uint32, []string]())
+
+	for _, v := range []string{"x", "y", "x", "z"} {
+		m.InsertDup(7, v)
+	}
+	m.InsertDup(8, "w")
+
+	if !m.DeleteValue(7, "x") {
+		t.Fatal("expected x to be deleted")
+	}
+	if m.DeleteValue(7, "w") {
+		t.Fatal("expected w not to be a value of 7")
+	}
+	if got := slices.Collect(m.Values(7)); !slices.Equal([]string{"y", "x", "z"}, got) {
+		t.Fatalf("expected [y x z], got %v", got)
+	}
+
+	if !m.DeleteValue(8, "w") {
+		t.Fatal("expected w to be deleted")
+	}
+	if _, ok := m.Search(8); ok {
+		t.Fatal("expected 8 to be deleted with its last value")
+	}
+
+	m.Insert(7, "only")
+	if v, ok := m.Search(7); !ok || v != "only" || m.Size() != 1 {
+		t.Fatalf("expected Insert to replace the values, got %s and size %d", v, m.Size())
+	}
+
+	if !m.Delete(7) || m.Size() != 0 {
+		t.Fatalf("expected an empty multimap, got size %d", m.Size())
+	}
+}
+
+func TestMultimapValuesStable(t *testing.T) {
+	m := art.NewMultimap(art.NewAlphaSortedTree[string, []int]())
+	for _, v := range []int{1, 2, 3, 4} {
+		m.InsertDup("k", v)
+	}
+
+	values := m.Values("k")
+	m.DeleteValue("k", 2)
+	m.InsertDup("k", 5)
+
+	if got := slices.Collect(values); !slices.Equal([]int{1, 2, 3, 4}, got) {
+		t.Fatalf("expected the earlier values to be unchanged, got %v", got)
+	}
+	if got := slices.Collect(m.Values("k")); !slices.Equal([]int{1, 3, 4, 5}, got) {
+		t.Fatalf("expected [1 3 4 5], got %v", got)
+	}
+}
+
+func TestMultimapFilledTree(t *testing.T) {
+	inner := art.NewAlphaSortedTree[string, []int]()
+	inner.Insert("a", []int{1, 2})
+	inner.Insert("b", []int{3})
+
+	m := art.NewMultimap(inner)
+	if m.Size() != 3 {
+		t.Fatalf("expected the values of the tree to be counted, got %d", m.Size())
+	}
+	if !m.Delete("a") || m.Size() != 1 {
+		t.Fatalf("expected a single value left, got %d", m.Size())
+	}
+}
@@ -56,7 +56,8 @@ type nodeLeaf[V any] interface {
 		*unsignedLeafNode[V] |
 		*signedLeafNode[V] |
 		*floatLeafNode[V] |
-		*compoundLeafNode[V]
+		*compoundLeafNode[V] |
+		*uuidLeafNode[V]
 }
 
 type nodeRef struct {
@@ -106,6 +107,61 @@ func (ref *nodeRef) findChild(b byte) *nodeRef {
 	return nil
 }
 
+// eachChild calls f with the children whose byte is in [lo, hi], in
+// ascending order.
+func (ref *nodeRef) eachChild(lo, hi byte, f func(byte, nodeRef)) {
+	switch ref.tag {
+	case nodeKind4:
+		n4 := (*node4)(ref.pointer)
+
+		for i := 0; i < int(n4.childrenLen); i++ {
+			b := getAtPos(n4.keys, i)
+			if b < lo {
+				continue
+			}
+			if b > hi {
+				break
+			}
+			f(b, n4.children[i])
+		}
+
+	case nodeKind16:
+		n16 := (*node16)(ref.pointer)
+
+		for i := 0; i < int(n16.childrenLen); i++ {
+			b := n16.keys[i]
+			if b < lo {
+				continue
+			}
+			if b > hi {
+				break
+			}
+			f(b, n16.children[i])
+		}
+
+	case nodeKind48:
+		n48 := (*node48)(ref.pointer)
+
+		for b := int(lo); b <= int(hi); b++ {
+			if idx := n48.keys[b]; idx != 0 {
+				f(byte(b), n48.children[idx-1])
+			}
+		}
+
+	case nodeKind256:
+		n256 := (*node256)(ref.pointer)
+
+		for b := int(lo); b <= int(hi); b++ {
+			if n256.children[b].pointer != nil {
+				f(byte(b), n256.children[b])
+			}
+		}
+
+	default:
+		panic("shouldn't be possible!")
+	}
+}
+
 func (ptr *nodeRef) addChild(b byte, child nodeRef) {
 	switch ptr.tag {
 	case nodeKind4:
@@ -0,0 +1,128 @@
+package art
+
+import (
+	"fmt"
+	"hash/maphash"
+	"time"
+	"unsafe"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+type treeOptions struct {
+	keyCopy    bool
+	binaryKeys bool
+	descending bool
+	totalOrder bool
+	checker    *keyChecker
+
+	labels   bool
+	labelSep byte
+
+	utf16 bool
+
+	now func() time.Time
+
+	maxEntries, maxBytes int
+	sizer                any // Sizer[V] of the CacheTree
+	lfu                  bool
+
+	fold      caseFolding
+	normalize bool
+	form      norm.Form
+}
+
+// Option configures the trees created by NewAlphaSortedTree,
+// NewUnsignedBinaryTree, NewSignedBinaryTree, NewFloatBinaryTree and
+// NewCompoundTree, and the wrappers such as NewTTLTree and NewCacheTree.
+type Option func(*treeOptions)
+
+func newTreeOptions(defaults treeOptions, opts []Option) treeOptions {
+	o := defaults
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// WithKeyCopy makes the tree copy the bytes of the keys it stores instead of
+// referencing the slice returned by the key transformation. This prevents a
+// caller mutating a key after Insert from corrupting the tree.
+//
+// It is enabled by default for []byte keys.
+func WithKeyCopy(enabled bool) Option {
+	return func(o *treeOptions) {
+		o.keyCopy = enabled
+	}
+}
+
+// WithBinaryKeys makes NewAlphaSortedTree accept keys containing 0x00 bytes,
+// such as hashes or encoded messages. The keys are stored with the
+// EscapedBinaryKey encoding instead of being terminated by 0x00.
+func WithBinaryKeys() Option {
+	return func(o *treeOptions) {
+		o.binaryKeys = true
+	}
+}
+
+// WithDescending makes NewUnsignedBinaryTree, NewSignedBinaryTree and
+// NewFloatBinaryTree order the keys from the largest to the smallest, as with
+// the Desc encoder. Minimum then returns the largest key and All starts with
+// it.
+func WithDescending() Option {
+	return func(o *treeOptions) {
+		o.descending = true
+	}
+}
+
+// WithTotalOrder makes NewFloatBinaryTree order the keys with
+// TotalOrderFloatKey instead of FloatBinaryKey, so that negative zero and
+// every NaN are distinct keys restored bit for bit.
+func WithTotalOrder() Option {
+	return func(o *treeOptions) {
+		o.totalOrder = true
+	}
+}
+
+// WithKeyCheck enables a debug mode in which the tree records a checksum of
+// every stored key and panics as soon as it finds a key that changed
+// underneath it.
+func WithKeyCheck() Option {
+	return func(o *treeOptions) {
+		o.checker = &keyChecker{
+			seed: maphash.MakeSeed(),
+			sums: make(map[unsafe.Pointer]uint64),
+		}
+	}
+}
+
+// keyChecker remembers the checksum of the key of each leaf.
+// A nil keyChecker does nothing.
+type keyChecker struct {
+	seed maphash.Seed
+	sums map[unsafe.Pointer]uint64
+}
+
+func (kc *keyChecker) record(leaf unsafe.Pointer, key []byte) {
+	if kc == nil {
+		return
+	}
+	kc.sums[leaf] = maphash.Bytes(kc.seed, key)
+}
+
+func (kc *keyChecker) check(leaf unsafe.Pointer, key []byte) {
+	if kc == nil {
+		return
+	}
+
+	if sum, ok := kc.sums[leaf]; ok && sum != maphash.Bytes(kc.seed, key) {
+		panic(fmt.Sprintf("art: stored key %q was modified after Insert", key))
+	}
+}
+
+func (kc *keyChecker) forget(leaf unsafe.Pointer) {
+	if kc == nil {
+		return
+	}
+	delete(kc.sums, leaf)
+}
@@ -0,0 +1,183 @@
+package art
+
+import (
+	"cmp"
+	"fmt"
+	"iter"
+	"reflect"
+	"unsafe"
+)
+
+// OrderedKey is implemented by the pointers to the types encoding
+// themselves into byte-wise comparable keys. AppendOrderedKey appends the
+// encoding to a buffer and ParseOrderedKey decodes it back. The encoding
+// doesn't need to be prefix-free, e.g. a version 1.9 can encode to a prefix
+// of 1.9.3: OrderedKeyEncoder escapes and terminates it.
+type OrderedKey interface {
+	AppendOrderedKey([]byte) []byte
+	ParseOrderedKey([]byte) error
+}
+
+// OrderedKeyEncoder is the BinaryComparableKey of the types implementing
+// OrderedKey. Like EscapedBinaryKey, it escapes the encoding and ends it with
+// 0x00 0x01, which keeps the order and makes the keys prefix-free. Restore
+// panics if ParseOrderedKey fails.
+type OrderedKeyEncoder[K any] struct{}
+
+func (oke OrderedKeyEncoder[K]) Transform(k K) ([]byte, []byte) {
+	b := oke.transformPrefix(k)
+	b = append(b, escapeByte, endByte)
+	return b, b
+}
+func (oke OrderedKeyEncoder[K]) Restore(b []byte) K {
+	k, _ := oke.RestorePrefix(b)
+	return k
+}
+
+// RestorePrefix restores the key encoded at the start of b and returns it
+// with the number of bytes it took.
+func (oke OrderedKeyEncoder[K]) RestorePrefix(b []byte) (K, int) {
+	raw, n := unescape(b)
+
+	var k K
+	if err := any(&k).(OrderedKey).ParseOrderedKey(raw); err != nil {
+		panic(fmt.Sprintf("art: invalid ordered key %x: %v", b[:n], err))
+	}
+	return k, n
+}
+
+// transformPrefix escapes the encoding without terminating it.
+func (oke OrderedKeyEncoder[K]) transformPrefix(k K) []byte {
+	b := any(&k).(OrderedKey).AppendOrderedKey(nil)
+	return appendEscaped(make([]byte, 0, len(b)+2), b)
+}
+
+// NewOrderedTree returns the tree of the underlying type of K: the alpha tree
+// for strings, and the unsigned, signed or float trees for numbers. It
+// accepts named types such as `type UserID uint64`. The types implementing
+// OrderedKey use their own encoding instead.
+func NewOrderedTree[K cmp.Ordered, V any](opts ...Option) Tree[K, V] {
+	if _, ok := any(new(K)).(OrderedKey); ok {
+		return NewCompoundTree[K, V](OrderedKeyEncoder[K]{}, opts...)
+	}
+
+	var k K
+	switch reflect.TypeOf(k).Kind() {
+	case reflect.String:
+		return convertTree[K](NewAlphaSortedTree[string, V](opts...))
+	case reflect.Uint8:
+		return convertTree[K](NewUnsignedBinaryTree[uint8, V](opts...))
+	case reflect.Uint16:
+		return convertTree[K](NewUnsignedBinaryTree[uint16, V](opts...))
+	case reflect.Uint32:
+		return convertTree[K](NewUnsignedBinaryTree[uint32, V](opts...))
+	case reflect.Uint64:
+		return convertTree[K](NewUnsignedBinaryTree[uint64, V](opts...))
+	case reflect.Uint:
+		return convertTree[K](NewUnsignedBinaryTree[uint, V](opts...))
+	case reflect.Uintptr:
+		if unsafe.Sizeof(k) == 8 {
+			return convertTree[K](NewUnsignedBinaryTree[uint64, V](opts...))
+		}
+		return convertTree[K](NewUnsignedBinaryTree[uint32, V](opts...))
+	case reflect.Int8:
+		return convertTree[K](NewSignedBinaryTree[int8, V](opts...))
+	case reflect.Int16:
+		return convertTree[K](NewSignedBinaryTree[int16, V](opts...))
+	case reflect.Int32:
+		return convertTree[K](NewSignedBinaryTree[int32, V](opts...))
+	case reflect.Int64:
+		return convertTree[K](NewSignedBinaryTree[int64, V](opts...))
+	case reflect.Int:
+		return convertTree[K](NewSignedBinaryTree[int, V](opts...))
+	case reflect.Float32:
+		return convertTree[K](NewFloatBinaryTree[float32, V](opts...))
+	case reflect.Float64:
+		return convertTree[K](NewFloatBinaryTree[float64, V](opts...))
+	}
+
+	panic("shouldn't be possible!")
+}
+
+// convertTree returns inner as a Tree[K, V] when K is B or a named type of
+// underlying type B.
+func convertTree[K, B nodeKey, V any](inner Tree[B, V]) Tree[K, V] {
+	if t, ok := any(inner).(Tree[K, V]); ok {
+		return t
+	}
+	return &convertedTree[K, B, V]{inner: inner, to: as[B, K], from: as[K, B]}
+}
+
+// as converts between two types of the same underlying type.
+func as[T, F any](f F) T {
+	return *(*T)(unsafe.Pointer(&f))
+}
+
+// convertedTree wraps a tree of keys of type B, converting the keys with to
+// and from.
+type convertedTree[K, B nodeKey, V any] struct {
+	inner Tree[B, V]
+	to    func(K) B
+	from  func(B) K
+}
+
+func (t *convertedTree[K, B, V]) Insert(key K, val V) { t.inner.Insert(t.to(key), val) }
+
+func (t *convertedTree[K, B, V]) Search(key K) (V, bool) { return t.inner.Search(t.to(key)) }
+
+func (t *convertedTree[K, B, V]) Delete(key K) bool { return t.inner.Delete(t.to(key)) }
+
+func (t *convertedTree[K, B, V]) Minimum() (K, V, bool) {
+	k, v, ok := t.inner.Minimum()
+	return t.from(k), v, ok
+}
+
+func (t *convertedTree[K, B, V]) Maximum() (K, V, bool) {
+	k, v, ok := t.inner.Maximum()
+	return t.from(k), v, ok
+}
+
+func (t *convertedTree[K, B, V]) All() iter.Seq2[K, V] { return t.converted(t.inner.All()) }
+
+func (t *convertedTree[K, B, V]) Backward() iter.Seq2[K, V] { return t.converted(t.inner.Backward()) }
+
+func (t *convertedTree[K, B, V]) Prefix(p K) iter.Seq2[K, V] {
+	return t.converted(t.inner.Prefix(t.to(p)))
+}
+
+func (t *convertedTree[K, B, V]) PrefixBackward(p K) iter.Seq2[K, V] {
+	return t.converted(t.inner.PrefixBackward(t.to(p)))
+}
+
+func (t *convertedTree[K, B, V]) TopK(k uint) iter.Seq2[K, V] { return t.converted(t.inner.TopK(k)) }
+
+func (t *convertedTree[K, B, V]) BottomK(k uint) iter.Seq2[K, V] {
+	return t.converted(t.inner.BottomK(k))
+}
+
+func (t *convertedTree[K, B, V]) Range(start, end K) iter.Seq2[K, V] {
+	return t.converted(t.inner.Range(t.to(start), t.to(end)))
+}
+
+func (t *convertedTree[K, B, V]) RangeWith(opts RangeOptions[K]) iter.Seq2[K, V] {
+	return t.converted(t.inner.RangeWith(RangeOptions[B]{
+		Start:   Bound[B]{key: t.to(opts.Start.key), kind: opts.Start.kind},
+		End:     Bound[B]{key: t.to(opts.End.key), kind: opts.End.kind},
+		Reverse: opts.Reverse,
+		Limit:   opts.Limit,
+	}))
+}
+
+func (t *convertedTree[K, B, V]) Size() int { return t.inner.Size() }
+
+func (t *convertedTree[K, B, V]) Stats() Stats { return t.inner.Stats() }
+
+func (t *convertedTree[K, B, V]) converted(seq iter.Seq2[B, V]) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for k, v := range seq {
+			if !yield(t.from(k), v) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package art_test
+
+import (
+	"cmp"
+	"encoding/binary"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/Clement-Jean/go-art"
+)
+
+type UserID uint64
+
+type Handle string
+
+type Celsius float32
+
+// Version orders dotted versions numerically, e.g. 1.9 before 1.10.
+type Version string
+
+func (v Version) AppendOrderedKey(b []byte) []byte {
+	for p := range strings.SplitSeq(string(v), ".") {
+		n, _ := strconv.ParseUint(p, 10, 32)
+		b = binary.BigEndian.AppendUint32(b, uint32(n))
+	}
+	return b
+}
+
+func (v *Version) ParseOrderedKey(b []byte) error {
+	if len(b)%4 != 0 {
+		return fmt.Errorf("invalid version length %d", len(b))
+	}
+
+	var parts []string
+	for ; len(b) > 0; b = b[4:] {
+		parts = append(parts, strconv.FormatUint(uint64(binary.BigEndian.Uint32(b)), 10))
+	}
+	*v = Version(strings.Join(parts, "."))
+	return nil
+}
+
+func collectOrdered[K cmp.Ordered](t *testing.T, keys []K, opts ...art.Option) []K {
+	t.Helper()
+
+	tr := art.NewOrderedTree[K, int](opts...)
+	for i, k := range keys {
+		tr.Insert(k, i)
+	}
+
+	for i, k := range keys {
+		if v, ok := tr.Search(k); !ok || v != i {
+			t.Fatalf("expected %v to be found with %d, got %d", k, i, v)
+		}
+	}
+
+	var got []K
+	for k, _ := range tr.All() {
+		got = append(got, k)
+	}
+	return got
+}
+
+func TestOrderedTree(t *testing.T) {
+	t.Run("named unsigned", func(t *testing.T) {
+		got := collectOrdered(t, []UserID{42, 7, 1 << 40})
+		if expected := []UserID{7, 42, 1 << 40}; !slices.Equal(expected, got) {
+			t.Fatalf("expected %v, got %v", expected, got)
+		}
+	})
+
+	t.Run("named string", func(t *testing.T) {
+		got := collectOrdered(t, []Handle{"bob", "alice", "al"})
+		if expected := []Handle{"al", "alice", "bob"}; !slices.Equal(expected, got) {
+			t.Fatalf("expected %v, got %v", expected, got)
+		}
+	})
+
+	t.Run("named float", func(t *testing.T) {
+		got := collectOrdered(t, []Celsius{21.5, -3, 0})
+		if expected := []Celsius{-3, 0, 21.5}; !slices.Equal(expected, got) {
+			t.Fatalf("expected %v, got %v", expected, got)
+		}
+	})
+
+	t.Run("int", func(t *testing.T) {
+		got := collectOrdered(t, []int{3, -1, 2}, art.WithDescending())
+		if expected := []int{3, 2, -1}; !slices.Equal(expected, got) {
+			t.Fatalf("expected %v, got %v", expected, got)
+		}
+	})
+
+	t.Run("ordered key", func(t *testing.T) {
+		got := collectOrdered(t, []Version{"1.10.0", "1.9.3", "2.0.0", "1.9", "1.9.12", "1"})
+		if expected := []Version{"1", "1.9", "1.9.3", "1.9.12", "1.10.0", "2.0.0"}; !slices.Equal(expected, got) {
+			t.Fatalf("expected %v, got %v", expected, got)
+		}
+	})
+}
+
+func TestOrderedTreePrefixRange(t *testing.T) {
+	tr := art.NewOrderedTree[Handle, int]()
+	for i, h := range []Handle{"alice", "alfred", "bob", "carol"} {
+		tr.Insert(h, i)
+	}
+
+	var got []Handle
+	for k, _ := range tr.Prefix("al") {
+		got = append(got, k)
+	}
+
+	if expected := []Handle{"alfred", "alice"}; !slices.Equal(expected, got) {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+
+	got = got[:0]
+	for k, _ := range tr.RangeWith(art.RangeOptions[Handle]{Start: art.Exclusive[Handle]("alice"), End: art.Inclusive[Handle]("bob")}) {
+		got = append(got, k)
+	}
+
+	if expected := []Handle{"bob"}; !slices.Equal(expected, got) {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+}
+
+func TestOrderedKeyPrefix(t *testing.T) {
+	tr := art.NewOrderedTree[Version, int]()
+	for i, v := range []Version{"1.9", "1.9.3", "1.90", "1.10.0"} {
+		tr.Insert(v, i)
+	}
+
+	var got []Version
+	for k, _ := range tr.Prefix("1.9") {
+		got = append(got, k)
+	}
+
+	if expected := []Version{"1.9", "1.9.3"}; !slices.Equal(expected, got) {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+}
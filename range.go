@@ -0,0 +1,84 @@
+package art
+
+import "bytes"
+
+type boundKind uint8
+
+const (
+	unbounded boundKind = iota
+	inclusive
+	exclusive
+)
+
+// Bound is one end of a range. The zero value is unbounded.
+type Bound[K nodeKey] struct {
+	key  K
+	kind boundKind
+}
+
+// Inclusive returns a bound including k.
+func Inclusive[K nodeKey](k K) Bound[K] { return Bound[K]{key: k, kind: inclusive} }
+
+// Exclusive returns a bound excluding k.
+func Exclusive[K nodeKey](k K) Bound[K] { return Bound[K]{key: k, kind: exclusive} }
+
+// Unbounded returns a bound extending to the minimum or the maximum key.
+func Unbounded[K nodeKey]() Bound[K] { return Bound[K]{} }
+
+// RangeOptions describes the interval iterated by RangeWith.
+type RangeOptions[K nodeKey] struct {
+	// Start and End are the lower and upper bounds of the interval, in
+	// tree order. Nothing is yielded when Start is after End.
+	Start, End Bound[K]
+
+	// Reverse iterates from End down to Start.
+	Reverse bool
+
+	// Limit stops the iteration after Limit entries when positive.
+	Limit int
+}
+
+// scanBounds is the transformed version of RangeOptions used by rangeScan.
+type scanBounds struct {
+	start, end         []byte
+	startKind, endKind boundKind
+	reverse            bool
+	limit              int
+}
+
+func newScanBounds[K nodeKey](opts RangeOptions[K], encode func(K) []byte) scanBounds {
+	b := scanBounds{
+		startKind: opts.Start.kind,
+		endKind:   opts.End.kind,
+		reverse:   opts.Reverse,
+		limit:     opts.Limit,
+	}
+
+	if b.startKind != unbounded {
+		b.start = encode(opts.Start.key)
+	}
+	if b.endKind != unbounded {
+		b.end = encode(opts.End.key)
+	}
+	return b
+}
+
+// rangeBounds returns the bounds used by Range: both ends are inclusive and
+// swapped when start is after end. An endless range has no upper bound.
+func rangeBounds(start, end []byte, endless bool) scanBounds {
+	if endless {
+		return scanBounds{start: start, startKind: inclusive}
+	}
+
+	if bytes.Compare(start, end) > 0 { // start > end
+		// IDEA: maybe do the iteration in reverse instead?
+		start, end = end, start
+	}
+
+	return scanBounds{
+		start:     start,
+		end:       end,
+		startKind: inclusive,
+		endKind:   inclusive,
+	}
+}
@@ -0,0 +1,209 @@
+package art_test
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	"github.com/Clement-Jean/go-art"
+)
+
+func TestRangeWithSigned(t *testing.T) {
+	keys := []int64{-30, -20, -10, 0, 10, 20, 30, 40}
+
+	tests := []struct {
+		name     string
+		opts     art.RangeOptions[int64]
+		expected []int64
+	}{
+		{
+			name:     "unbounded",
+			opts:     art.RangeOptions[int64]{},
+			expected: keys,
+		},
+		{
+			name:     "half-open",
+			opts:     art.RangeOptions[int64]{Start: art.Inclusive[int64](-10), End: art.Exclusive[int64](20)},
+			expected: []int64{-10, 0, 10},
+		},
+		{
+			name:     "open",
+			opts:     art.RangeOptions[int64]{Start: art.Exclusive[int64](-10), End: art.Exclusive[int64](20)},
+			expected: []int64{0, 10},
+		},
+		{
+			name:     "unbounded start",
+			opts:     art.RangeOptions[int64]{End: art.Inclusive[int64](0)},
+			expected: []int64{-30, -20, -10, 0},
+		},
+		{
+			name:     "unbounded end",
+			opts:     art.RangeOptions[int64]{Start: art.Exclusive[int64](25), End: art.Unbounded[int64]()},
+			expected: []int64{30, 40},
+		},
+		{
+			name:     "newest first",
+			opts:     art.RangeOptions[int64]{Start: art.Inclusive[int64](-10), End: art.Exclusive[int64](30), Reverse: true},
+			expected: []int64{20, 10, 0, -10},
+		},
+		{
+			name:     "limit",
+			opts:     art.RangeOptions[int64]{Start: art.Inclusive[int64](-25), Limit: 2},
+			expected: []int64{-20, -10},
+		},
+		{
+			name:     "reverse limit",
+			opts:     art.RangeOptions[int64]{End: art.Exclusive[int64](40), Reverse: true, Limit: 3},
+			expected: []int64{30, 20, 10},
+		},
+		{
+			name:     "start after end",
+			opts:     art.RangeOptions[int64]{Start: art.Inclusive[int64](20), End: art.Inclusive[int64](-20)},
+			expected: nil,
+		},
+		{
+			name:     "empty exclusive",
+			opts:     art.RangeOptions[int64]{Start: art.Exclusive[int64](10), End: art.Exclusive[int64](10)},
+			expected: nil,
+		},
+	}
+
+	tr := art.NewSignedBinaryTree[int64, int64]()
+	for _, k := range keys {
+		tr.Insert(k, k)
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("range-%s", tt.name), func(t *testing.T) {
+			var got []int64
+			for k, _ := range tr.RangeWith(tt.opts) {
+				got = append(got, k)
+			}
+
+			if !slices.Equal(tt.expected, got) {
+				t.Fatalf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestRangeWithAlpha(t *testing.T) {
+	tr := art.NewAlphaSortedTree[string, int]()
+	keys := []string{"a", "ab", "abc", "abd", "b", "ba", "bab", "c"}
+
+	for i, k := range keys {
+		tr.Insert(k, i)
+	}
+
+	var got []string
+	opts := art.RangeOptions[string]{Start: art.Exclusive("ab"), End: art.Exclusive("ba"), Reverse: true}
+	for k, _ := range tr.RangeWith(opts) {
+		got = append(got, k)
+	}
+
+	if expected := []string{"b", "abd", "abc"}; !slices.Equal(expected, got) {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+}
+
+func TestRangeWithRandom(t *testing.T) {
+	r := rand.New(rand.NewPCG(3, 4))
+	tr := art.NewUnsignedBinaryTree[uint32, int]()
+
+	var keys []uint32
+	for i := range 50_000 {
+		k := r.Uint32N(1 << 24)
+		tr.Insert(k, i)
+		keys = append(keys, k)
+	}
+
+	slices.Sort(keys)
+	keys = slices.Compact(keys)
+
+	// kind 0 is inclusive, 1 exclusive and 2 unbounded
+	bound := func(k uint32, kind int) art.Bound[uint32] {
+		switch kind {
+		case 0:
+			return art.Inclusive(k)
+		case 1:
+			return art.Exclusive(k)
+		}
+		return art.Unbounded[uint32]()
+	}
+
+	for range 200 {
+		start, end := r.Uint32N(1<<24), r.Uint32N(1<<24)
+		startKind, endKind := r.IntN(3), r.IntN(3)
+		opts := art.RangeOptions[uint32]{
+			Start:   bound(start, startKind),
+			End:     bound(end, endKind),
+			Reverse: r.IntN(2) == 0,
+			Limit:   r.IntN(20),
+		}
+
+		var expected []uint32
+		for _, k := range keys {
+			if (startKind == 0 && k < start) || (startKind == 1 && k <= start) {
+				continue
+			}
+			if (endKind == 0 && k > end) || (endKind == 1 && k >= end) {
+				continue
+			}
+			expected = append(expected, k)
+		}
+		if opts.Reverse {
+			slices.Reverse(expected)
+		}
+		if opts.Limit > 0 && len(expected) > opts.Limit {
+			expected = expected[:opts.Limit]
+		}
+
+		var got []uint32
+		for k, _ := range tr.RangeWith(opts) {
+			got = append(got, k)
+		}
+
+		if !slices.Equal(expected, got) {
+			t.Fatalf("%+v: expected %v, got %v", opts, expected, got)
+		}
+	}
+}
+
+func TestRangeWithWords(t *testing.T) {
+	r := rand.New(rand.NewPCG(5, 6))
+	words := loadTestFile("testdata/words.txt")
+	tr := art.NewAlphaSortedTree[string, int]()
+
+	var keys []string
+	for i, w := range words {
+		tr.Insert(string(w), i)
+		keys = append(keys, string(w))
+	}
+
+	slices.Sort(keys)
+	keys = slices.Compact(keys)
+
+	for range 100 {
+		start, end := keys[r.IntN(len(keys))], keys[r.IntN(len(keys))]
+		if start > end {
+			start, end = end, start
+		}
+		start = start[:r.IntN(len(start)+1)] // bounds aren't always keys
+
+		lo, _ := slices.BinarySearch(keys, start)
+		hi, _ := slices.BinarySearch(keys, end)
+		expected := slices.Clone(keys[lo:hi])
+		slices.Reverse(expected)
+
+		var got []string
+		opts := art.RangeOptions[string]{Start: art.Inclusive(start), End: art.Exclusive(end), Reverse: true}
+		for k, _ := range tr.RangeWith(opts) {
+			got = append(got, k)
+		}
+
+		if !slices.Equal(expected, got) {
+			t.Fatalf("[%q, %q): expected %d keys, got %d", start, end, len(expected), len(got))
+		}
+	}
+}
@@ -0,0 +1,166 @@
+package art
+
+import (
+	"bytes"
+	"iter"
+)
+
+// Set is an ordered set of keys. Its leaves only store the keys.
+type Set[K nodeKey] interface {
+	// Add adds a key and reports whether it wasn't in the set.
+	Add(K) bool
+	Has(K) bool
+	// Remove removes a key and reports whether it was in the set.
+	Remove(K) bool
+
+	Minimum() (K, bool)
+	Maximum() (K, bool)
+	All() iter.Seq[K]
+	Backward() iter.Seq[K]
+	Prefix(K) iter.Seq[K]
+	PrefixBackward(K) iter.Seq[K]
+	Range(K, K) iter.Seq[K]
+	RangeWith(RangeOptions[K]) iter.Seq[K]
+
+	Size() int
+	Stats() Stats
+
+	// encodeKey returns the key compared by the set algebra.
+	encodeKey(K) []byte
+}
+
+// keySet adapts a Tree[K, struct{}], whose leaves take no space for the values.
+type keySet[K nodeKey] struct {
+	inner  Tree[K, struct{}]
+	encode func(K) []byte
+}
+
+func (s *keySet[K]) Add(key K) bool {
+	n := s.inner.Size()
+	s.inner.Insert(key, struct{}{})
+	return s.inner.Size() != n
+}
+
+func (s *keySet[K]) Has(key K) bool {
+	_, ok := s.inner.Search(key)
+	return ok
+}
+
+func (s *keySet[K]) Remove(key K) bool { return s.inner.Delete(key) }
+
+func (s *keySet[K]) Minimum() (K, bool) {
+	k, _, ok := s.inner.Minimum()
+	return k, ok
+}
+
+func (s *keySet[K]) Maximum() (K, bool) {
+	k, _, ok := s.inner.Maximum()
+	return k, ok
+}
+
+func (s *keySet[K]) All() iter.Seq[K] { return keys(s.inner.All()) }
+
+func (s *keySet[K]) Backward() iter.Seq[K] { return keys(s.inner.Backward()) }
+
+func (s *keySet[K]) Prefix(p K) iter.Seq[K] { return keys(s.inner.Prefix(p)) }
+
+func (s *keySet[K]) PrefixBackward(p K) iter.Seq[K] { return keys(s.inner.PrefixBackward(p)) }
+
+func (s *keySet[K]) Range(start, end K) iter.Seq[K] { return keys(s.inner.Range(start, end)) }
+
+func (s *keySet[K]) RangeWith(opts RangeOptions[K]) iter.Seq[K] {
+	return keys(s.inner.RangeWith(opts))
+}
+
+func (s *keySet[K]) Size() int { return s.inner.Size() }
+
+func (s *keySet[K]) Stats() Stats { return s.inner.Stats() }
+
+func (s *keySet[K]) encodeKey(key K) []byte { return s.encode(key) }
+
+func keys[K nodeKey, V any](seq iter.Seq2[K, V]) iter.Seq[K] {
+	return func(yield func(K) bool) {
+		for k := range seq {
+			if !yield(k) {
+				return
+			}
+		}
+	}
+}
+
+// The set algebra needs a and b to order their keys the same way, e.g. to be
+// created by the same constructor with the same options. The keys are
+// yielded in order.
+
+// Union iterates over the keys in a or in b.
+func Union[K nodeKey](a, b Set[K]) iter.Seq[K] { return merge(a, b, true) }
+
+// SymmetricDifference iterates over the keys in a or in b but not in both.
+func SymmetricDifference[K nodeKey](a, b Set[K]) iter.Seq[K] { return merge(a, b, false) }
+
+// Intersection iterates over the keys in both a and b. It looks up the keys
+// of the smallest set in the other one.
+func Intersection[K nodeKey](a, b Set[K]) iter.Seq[K] {
+	if a.Size() > b.Size() {
+		a, b = b, a
+	}
+	return filter(a, b, true)
+}
+
+// Difference iterates over the keys in a but not in b.
+func Difference[K nodeKey](a, b Set[K]) iter.Seq[K] { return filter(a, b, false) }
+
+// filter iterates over the keys of a which are in b, or which aren't.
+func filter[K nodeKey](a, b Set[K], in bool) iter.Seq[K] {
+	return func(yield func(K) bool) {
+		for k := range a.All() {
+			if b.Has(k) == in && !yield(k) {
+				return
+			}
+		}
+	}
+}
+
+// merge iterates over the keys of a and b side by side, yielding the keys in
+// both only when both is set.
+func merge[K nodeKey](a, b Set[K], both bool) iter.Seq[K] {
+	return func(yield func(K) bool) {
+		nextA, stopA := iter.Pull(a.All())
+		defer stopA()
+		nextB, stopB := iter.Pull(b.All())
+		defer stopB()
+
+		ka, okA := nextA()
+		kb, okB := nextB()
+		for okA || okB {
+			c := 0
+			switch {
+			case !okB:
+				c = -1
+			case !okA:
+				c = 1
+			default:
+				c = bytes.Compare(a.encodeKey(ka), b.encodeKey(kb))
+			}
+
+			switch {
+			case c < 0:
+				if !yield(ka) {
+					return
+				}
+				ka, okA = nextA()
+			case c > 0:
+				if !yield(kb) {
+					return
+				}
+				kb, okB = nextB()
+			default:
+				if both && !yield(ka) {
+					return
+				}
+				ka, okA = nextA()
+				kb, okB = nextB()
+			}
+		}
+	}
+}
@@ -0,0 +1,153 @@
+package art_test
+
+import (
+	"slices"
+	"strconv"
+	"testing"
+	"unsafe"
+
+	"github.com/Clement-Jean/go-art"
+)
+
+func TestAlphaSet(t *testing.T) {
+	s := art.NewAlphaSortedSet[string]()
+
+	for _, k := range []string{"banana", "apple", "cherry", "apple", "apricot"} {
+		s.Add(k)
+	}
+
+	if s.Size() != 4 || s.Add("apple") || !s.Add("date") {
+		t.Fatalf("expected Add to report the new keys, got size %d", s.Size())
+	}
+	if !s.Has("cherry") || s.Has("fig") {
+		t.Fatal("expected cherry and not fig")
+	}
+	if !s.Remove("date") || s.Remove("date") {
+		t.Fatal("expected date to be removed once")
+	}
+
+	if got := slices.Collect(s.All()); !slices.Equal([]string{"apple", "apricot", "banana", "cherry"}, got) {
+		t.Fatalf("expected the keys in order, got %v", got)
+	}
+	if got := slices.Collect(s.PrefixBackward("ap")); !slices.Equal([]string{"apricot", "apple"}, got) {
+		t.Fatalf("expected [apricot apple], got %v", got)
+	}
+	if k, ok := s.Maximum(); !ok || k != "cherry" {
+		t.Fatalf("expected maximum cherry, got %s", k)
+	}
+}
+
+func TestNumericSets(t *testing.T) {
+	u := art.NewUnsignedBinarySet[uint32]()
+	i := art.NewSignedBinarySet[int64](art.WithDescending())
+	f := art.NewFloatBinarySet[float64]()
+
+	for _, n := range []int{3, -1, 2, 10, -7} {
+		u.Add(uint32(n + 7))
+		i.Add(int64(n))
+		f.Add(float64(n) / 2)
+	}
+
+	if got := slices.Collect(u.Range(6, 10)); !slices.Equal([]uint32{6, 9, 10}, got) {
+		t.Fatalf("expected [6 9 10], got %v", got)
+	}
+	if got := slices.Collect(i.All()); !slices.Equal([]int64{10, 3, 2, -1, -7}, got) {
+		t.Fatalf("expected the keys in descending order, got %v", got)
+	}
+	if k, ok := f.Minimum(); !ok || k != -3.5 {
+		t.Fatalf("expected minimum -3.5, got %v", k)
+	}
+}
+
+func TestSetLeaves(t *testing.T) {
+	// the leaves of the sets only store the keys
+	inline := unsafe.Sizeof(struct{ key [16]byte }{})
+	referenced := unsafe.Sizeof(struct {
+		key *byte
+		len uint32
+	}{})
+
+	tests := []struct {
+		name     string
+		fill     func() art.Stats
+		expected uintptr
+	}{
+		{"alpha", fillSet(art.NewAlphaSortedSet[string](), strconv.Itoa), referenced},
+		{"unsigned", fillSet(art.NewUnsignedBinarySet[uint64](), func(i int) uint64 { return uint64(i) }), referenced},
+		{"signed", fillSet(art.NewSignedBinarySet[int64](), func(i int) int64 { return int64(i) }), referenced},
+		{"float", fillSet(art.NewFloatBinarySet[float64](), func(i int) float64 { return float64(i) }), referenced},
+		{"compound", fillSet(art.NewCompoundSet(art.EscapedBinaryKey[string]{}), strconv.Itoa), referenced},
+		{"uuid", fillSet(art.NewUUIDSet[UUID](), func(i int) UUID { return UUID{byte(i), 1, 2, byte(i * 7)} }), inline},
+	}
+
+	for _, tt := range tests {
+		s := tt.fill()
+		if s.Leaves != 100 || s.LeafBytes != 100*int(tt.expected) {
+			t.Fatalf("%s: expected 100 leaves of %d bytes, got %d leaves and %d bytes", tt.name, tt.expected, s.Leaves, s.LeafBytes)
+		}
+	}
+}
+
+// fillSet returns a function adding 100 keys to s and returning its stats.
+func fillSet[K any](s art.Set[K], key func(int) K) func() art.Stats {
+	return func() art.Stats {
+		for i := range 100 {
+			s.Add(key(i))
+		}
+		return s.Stats()
+	}
+}
+
+func TestSetAlgebra(t *testing.T) {
+	a := art.NewSignedBinarySet[int]()
+	b := art.NewSignedBinarySet[int]()
+
+	for _, k := range []int{-5, 1, 2, 3, 8} {
+		a.Add(k)
+	}
+	for _, k := range []int{-9, 2, 3, 4, 8, 100} {
+		b.Add(k)
+	}
+
+	tests := []struct {
+		name          string
+		got, expected []int
+	}{
+		{"union", slices.Collect(art.Union(a, b)), []int{-9, -5, 1, 2, 3, 4, 8, 100}},
+		{"intersection", slices.Collect(art.Intersection(a, b)), []int{2, 3, 8}},
+		{"intersection reversed", slices.Collect(art.Intersection(b, a)), []int{2, 3, 8}},
+		{"difference", slices.Collect(art.Difference(a, b)), []int{-5, 1}},
+		{"symmetric difference", slices.Collect(art.SymmetricDifference(a, b)), []int{-9, -5, 1, 4, 100}},
+	}
+
+	for _, tt := range tests {
+		if !slices.Equal(tt.expected, tt.got) {
+			t.Fatalf("%s: expected %v, got %v", tt.name, tt.expected, tt.got)
+		}
+	}
+
+	var got []int
+	for k := range art.Union(a, b) {
+		if k > 2 {
+			break
+		}
+		got = append(got, k)
+	}
+	if expected := []int{-9, -5, 1, 2}; !slices.Equal(expected, got) {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+}
+
+func TestCompoundSetUnion(t *testing.T) {
+	a := art.NewCompoundSet(art.EscapedBinaryKey[string]{})
+	b := art.NewCompoundSet(art.EscapedBinaryKey[string]{})
+
+	a.Add("x\x00y")
+	a.Add("x")
+	b.Add("x\x00")
+	b.Add("x")
+
+	if got := slices.Collect(art.Union(a, b)); !slices.Equal([]string{"x", "x\x00", "x\x00y"}, got) {
+		t.Fatalf("expected the keys in order, got %q", got)
+	}
+}
@@ -1,5 +1,38 @@
 package art
 
-func NewSignedBinaryTree[K ints, V any]() Tree[K, V] {
-	return &signedSortedTree[K, V]{}
+func NewSignedBinaryTree[K ints, V any](opts ...Option) Tree[K, V] {
+	return &signedSortedTree[K, V]{
+		signedCodec: signedCodec[K]{opts: newTreeOptions(treeOptions{}, opts)},
+	}
+}
+
+// NewSignedBinarySet returns a set ordering the keys like
+// NewSignedBinaryTree.
+func NewSignedBinarySet[K ints](opts ...Option) Set[K] {
+	t := &signedSortedTree[K, struct{}]{
+		signedCodec: signedCodec[K]{opts: newTreeOptions(treeOptions{}, opts)},
+	}
+	return &keySet[K]{inner: t, encode: t.encodeKey}
+}
+
+// signedCodec encodes the keys of the signed trees.
+type signedCodec[K ints] struct {
+	bck  SignedBinaryKey[K]
+	opts treeOptions
+}
+
+func (c *signedCodec[K]) encodeKey(key K) []byte {
+	_, keyS := c.bck.Transform(key)
+	if c.opts.descending {
+		invert(keyS, keyS)
+	}
+	return keyS
+}
+
+func (c *signedCodec[K]) decodeKey(b []byte) K {
+	if c.opts.descending {
+		var buf [8]byte
+		return c.bck.Restore(invert(buf[:len(b)], b))
+	}
+	return c.bck.Restore(b)
 }
@@ -0,0 +1,161 @@
+package art
+
+import (
+	"fmt"
+	"strings"
+	"unsafe"
+)
+
+// Stats describes the shape and the memory footprint of a tree.
+type Stats struct {
+	Node4, Node16, Node48, Node256 int
+	Leaves                         int
+
+	// Depths is the depth histogram of the leaves: Depths[d] is the number
+	// of leaves sitting below d inner nodes.
+	Depths []int
+
+	// AvgFanOut is the average number of children per inner node.
+	AvgFanOut float64
+
+	// PrefixBytes is the total length of the compressed paths stored in
+	// inner nodes, including the part that doesn't fit in a node.
+	PrefixBytes int
+
+	// NodeBytes, KeyBytes and LeafBytes are the bytes used by inner nodes,
+	// by the keys referenced from the leaves and by the leaves themselves.
+	NodeBytes, KeyBytes, LeafBytes int
+
+	// OverflowLookups is the number of leaves loaded to read the part of a
+	// compressed path longer than maxPrefixLen, by Insert and by the prefix
+	// and range scans. Search and Delete never load one: they skip that part
+	// and compare the whole key at the leaf.
+	OverflowLookups int
+}
+
+// Bytes returns the total number of bytes used by the tree.
+func (s Stats) Bytes() int {
+	return s.NodeBytes + s.KeyBytes + s.LeafBytes
+}
+
+func (s Stats) String() string {
+	var sb strings.Builder
+
+	for kind, count := range [...]int{s.Node4, s.Node16, s.Node48, s.Node256, s.Leaves} {
+		fmt.Fprintf(&sb, "%s: %d\n", nodeKind(kind), count)
+	}
+	fmt.Fprintf(&sb, "depths: %v\n", s.Depths)
+	fmt.Fprintf(&sb, "fan-out: %.2f\n", s.AvgFanOut)
+	fmt.Fprintf(&sb, "prefix bytes: %d\n", s.PrefixBytes)
+	fmt.Fprintf(&sb, "bytes: %d (nodes: %d, keys: %d, leaves: %d)\n", s.Bytes(), s.NodeBytes, s.KeyBytes, s.LeafBytes)
+	fmt.Fprintf(&sb, "overflow lookups: %d", s.OverflowLookups)
+	return sb.String()
+}
+
+var nodeSizes = [nodeKindLeaf]int{
+	int(unsafe.Sizeof(node4{})),
+	int(unsafe.Sizeof(node16{})),
+	int(unsafe.Sizeof(node48{})),
+	int(unsafe.Sizeof(node256{})),
+}
+
+func stats[V any, L nodeLeaf[V]](root nodeRef, leafSize uintptr) Stats {
+	type frame struct {
+		ref   nodeRef
+		depth int
+	}
+
+	var (
+		s        Stats
+		kinds    [nodeKindLeaf + 1]int
+		children int
+	)
+
+	if root.pointer == nil {
+		return s
+	}
+
+	q := []frame{{ref: root}}
+	for len(q) != 0 {
+		f := q[len(q)-1]
+		q = q[:len(q)-1]
+
+		kinds[f.ref.tag]++
+
+		if f.ref.tag == nodeKindLeaf {
+			leaf := (L)(f.ref.pointer)
+			key, transformKey := leaf.getKey(), leaf.getTransformKey()
+
+			s.KeyBytes += len(key)
+			if unsafe.SliceData(key) != unsafe.SliceData(transformKey) {
+				s.KeyBytes += len(transformKey)
+			}
+
+			for len(s.Depths) <= f.depth {
+				s.Depths = append(s.Depths, 0)
+			}
+			s.Depths[f.depth]++
+			continue
+		}
+
+		s.NodeBytes += nodeSizes[f.ref.tag]
+		s.PrefixBytes += int(f.ref.node().prefixLen)
+
+		switch f.ref.tag {
+		case nodeKind4:
+			n4 := (*node4)(f.ref.pointer)
+
+			for i := uint8(0); i < n4.childrenLen; i++ {
+				q = append(q, frame{ref: n4.children[i], depth: f.depth + 1})
+				children++
+			}
+
+		case nodeKind16:
+			n16 := (*node16)(f.ref.pointer)
+
+			for i := uint8(0); i < n16.childrenLen; i++ {
+				q = append(q, frame{ref: n16.children[i], depth: f.depth + 1})
+				children++
+			}
+
+		case nodeKind48:
+			n48 := (*node48)(f.ref.pointer)
+
+			for i := 0; i < 256; i++ {
+				idx := n48.keys[i]
+				if idx == 0 {
+					continue
+				}
+				q = append(q, frame{ref: n48.children[idx-1], depth: f.depth + 1})
+				children++
+			}
+
+		case nodeKind256:
+			n256 := (*node256)(f.ref.pointer)
+
+			for i := 0; i < 256; i++ {
+				if n256.children[i].pointer == nil {
+					continue
+				}
+				q = append(q, frame{ref: n256.children[i], depth: f.depth + 1})
+				children++
+			}
+
+		default:
+			panic("shouldn't be possible!")
+		}
+	}
+
+	s.Node4 = kinds[nodeKind4]
+	s.Node16 = kinds[nodeKind16]
+	s.Node48 = kinds[nodeKind48]
+	s.Node256 = kinds[nodeKind256]
+	s.Leaves = kinds[nodeKindLeaf]
+	s.LeafBytes = s.Leaves * int(leafSize)
+
+	if inner := s.Node4 + s.Node16 + s.Node48 + s.Node256; inner != 0 {
+		s.AvgFanOut = float64(children) / float64(inner)
+	}
+
+	return s
+}
@@ -0,0 +1,128 @@
+package art_test
+
+import (
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/Clement-Jean/go-art"
+)
+
+func TestStatsEmpty(t *testing.T) {
+	tr := art.NewAlphaSortedTree[string, int]()
+	s := tr.Stats()
+
+	if s.Leaves != 0 || s.Bytes() != 0 || len(s.Depths) != 0 {
+		t.Fatalf("expected empty stats, got %+v", s)
+	}
+}
+
+func TestStatsWords(t *testing.T) {
+	tr := art.NewAlphaSortedTree[[]byte, int]()
+	words := loadTestFile("testdata/words.txt")
+
+	for i, w := range words {
+		tr.Insert(w, i)
+	}
+
+	s := tr.Stats()
+
+	if s.Leaves != tr.Size() {
+		t.Fatalf("expected %d leaves, got %d", tr.Size(), s.Leaves)
+	}
+
+	leaves := 0
+	for _, count := range s.Depths {
+		leaves += count
+	}
+
+	if leaves != s.Leaves {
+		t.Fatalf("expected depth histogram to sum to %d, got %d", s.Leaves, leaves)
+	}
+
+	if s.Node4 == 0 || s.Node256 == 0 {
+		t.Fatalf("expected node4 and node256, got %+v", s)
+	}
+
+	if s.AvgFanOut <= 1 {
+		t.Fatalf("expected a fan-out greater than 1, got %f", s.AvgFanOut)
+	}
+
+	if s.KeyBytes <= len(words) {
+		t.Fatalf("expected key bytes to account for the terminators, got %d", s.KeyBytes)
+	}
+
+	if str := s.String(); !strings.Contains(str, "NODE_4") || !strings.Contains(str, "NODE_LEAF") {
+		t.Fatalf("expected node kinds in %q", str)
+	}
+}
+
+func TestStatsOverflow(t *testing.T) {
+	tr := art.NewAlphaSortedTree[string, int]()
+
+	tr.Insert("this:key:has:a:long:common:prefix:1", 1)
+	tr.Insert("this:key:has:a:long:common:prefix:2", 2)
+	tr.Insert("this:key:has:a:long:common:prefix:3", 3)
+
+	s := tr.Stats()
+
+	// only the third insert compares the path past maxPrefixLen
+	if s.OverflowLookups != 1 {
+		t.Fatalf("expected 1 overflow lookup, got %d", s.OverflowLookups)
+	}
+
+	if _, ok := tr.Search("this:key:has:a:long:common:prefix:2"); !ok {
+		t.Fatal("expected the key to be found")
+	}
+	tr.Insert("this:key:has:a:short", 4)
+	for range tr.Range("this:key:has:a:long:common:prefix:0", "this:key:has:a:long:common:prefix:9") {
+	}
+
+	// the insert compares the path and then splits it, and the range scan
+	// goes through the two long paths left
+	if got := tr.Stats().OverflowLookups; got != 5 {
+		t.Fatalf("expected 5 overflow lookups, got %d", got)
+	}
+
+	if s.PrefixBytes <= 10 {
+		t.Fatalf("expected the long prefix to be accounted, got %d", s.PrefixBytes)
+	}
+}
+
+func TestStatsConcurrentPrefix(t *testing.T) {
+	tr := art.NewAlphaSortedTree[string, int]()
+	tr.Insert("this:key:has:a:long:common:prefix:1", 1)
+	tr.Insert("this:key:has:a:long:common:prefix:2", 2)
+
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range tr.Prefix("this:key:has:a:long") {
+			}
+		}()
+	}
+	wg.Wait()
+
+	if s := tr.Stats(); s.OverflowLookups < 4 {
+		t.Fatalf("expected the prefix lookups to be counted, got %d", s.OverflowLookups)
+	}
+}
+
+func TestStatsCollate(t *testing.T) {
+	tr := art.NewCollationSortedTree[string, int]()
+
+	tr.Insert("apple", 1)
+	tr.Insert("banana", 2)
+
+	s := tr.Stats()
+
+	if s.Leaves != 2 {
+		t.Fatalf("expected 2 leaves, got %d", s.Leaves)
+	}
+
+	if s.KeyBytes <= len("apple")+len("banana") {
+		t.Fatalf("expected collation keys to be accounted, got %d", s.KeyBytes)
+	}
+}
@@ -0,0 +1,221 @@
+package art
+
+import (
+	"bytes"
+	"iter"
+	"slices"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// SuffixTree is a Tree also answering suffix queries.
+type SuffixTree[K chars, V any] interface {
+	Tree[K, V]
+
+	// Suffix iterates over the keys ending with the given suffix.
+	Suffix(K) iter.Seq2[K, V]
+}
+
+// NewSuffixTree returns an alpha tree indexing the reversed keys, so that
+// suffix queries are prefix queries. The keys are returned as inserted but
+// the iterations follow the order of the reversed keys, e.g. "b.com" before
+// "a.net".
+//
+// With WithLabelSeparator, the keys are reversed label by label instead of
+// byte by byte: "www.example.com" is indexed as "com.example.www". With the
+// options reading the keys as text, such as WithSimpleCaseFolding,
+// WithNormalization or WithUTF16Order, the keys are reversed rune by rune so
+// that they stay valid UTF-8, and with WithNormalization by normalization
+// segments so that the combining marks stay after their base character.
+//
+// Prefix and PrefixBackward have no index to use and scan the whole tree.
+func NewSuffixTree[K chars, V any](opts ...Option) SuffixTree[K, V] {
+	o := newTreeOptions(treeOptions{}, opts)
+
+	return &suffixTree[K, V]{
+		inner:    NewAlphaSortedTree[K, V](opts...),
+		runes:    o.foldsText() || o.utf16,
+		segments: o.normalize,
+		form:     o.form,
+		labels:   o.labels,
+		labelSep: o.labelSep,
+	}
+}
+
+// WithLabelSeparator makes NewSuffixTree reverse the keys label by label,
+// the labels being separated by sep, e.g. '.' for host names. Suffix then
+// only matches whole labels.
+func WithLabelSeparator(sep byte) Option {
+	return func(o *treeOptions) {
+		o.labels = true
+		o.labelSep = sep
+	}
+}
+
+type suffixTree[K chars, V any] struct {
+	inner    Tree[K, V]
+	runes    bool
+	segments bool
+	form     norm.Form
+	labels   bool
+	labelSep byte
+}
+
+// reverse reverses the bytes, the runes, the normalization segments or the
+// labels of k. It is its own inverse, for valid UTF-8 when reversing the runes
+// and for normalized text when reversing the segments.
+func (t *suffixTree[K, V]) reverse(k K) K {
+	if t.segments && !t.labels {
+		return K(reverseSegments([]byte(k), t.form))
+	}
+	if t.runes && !t.labels {
+		return K(reverseRunes([]byte(k)))
+	}
+	if !t.labels {
+		b := []byte(k)
+		if _, isBytes := any(k).([]byte); isBytes {
+			b = bytes.Clone(b)
+		}
+		slices.Reverse(b)
+		return K(b)
+	}
+
+	labels := bytes.Split([]byte(k), []byte{t.labelSep})
+	slices.Reverse(labels)
+	return K(bytes.Join(labels, []byte{t.labelSep}))
+}
+
+// reverseRunes returns the runes of b in reverse order. The bytes which
+// aren't valid UTF-8 are moved one by one.
+func reverseRunes(b []byte) []byte {
+	r := make([]byte, 0, len(b))
+	for len(b) > 0 {
+		_, n := utf8.DecodeLastRune(b)
+		r = append(r, b[len(b)-n:]...)
+		b = b[:len(b)-n]
+	}
+	return r
+}
+
+// reverseSegments returns the normalization segments of b in reverse order,
+// a segment being a base character followed by its combining marks.
+func reverseSegments(b []byte, form norm.Form) []byte {
+	var ends []int
+	for i := 0; i < len(b); {
+		n := form.NextBoundary(b[i:], true)
+		if n <= 0 {
+			n = len(b) - i
+		}
+		i += n
+		ends = append(ends, i)
+	}
+
+	r := make([]byte, 0, len(b))
+	for i := len(ends) - 1; i >= 0; i-- {
+		start := 0
+		if i > 0 {
+			start = ends[i-1]
+		}
+		r = append(r, b[start:ends[i]]...)
+	}
+	return r
+}
+
+func (t *suffixTree[K, V]) Insert(key K, val V) { t.inner.Insert(t.reverse(key), val) }
+
+func (t *suffixTree[K, V]) Search(key K) (V, bool) { return t.inner.Search(t.reverse(key)) }
+
+func (t *suffixTree[K, V]) Delete(key K) bool { return t.inner.Delete(t.reverse(key)) }
+
+func (t *suffixTree[K, V]) Minimum() (K, V, bool) {
+	k, v, ok := t.inner.Minimum()
+	return t.reverse(k), v, ok
+}
+
+func (t *suffixTree[K, V]) Maximum() (K, V, bool) {
+	k, v, ok := t.inner.Maximum()
+	return t.reverse(k), v, ok
+}
+
+func (t *suffixTree[K, V]) All() iter.Seq2[K, V] { return t.reversed(t.inner.All()) }
+
+func (t *suffixTree[K, V]) Backward() iter.Seq2[K, V] { return t.reversed(t.inner.Backward()) }
+
+// Prefix iterates over the keys starting with p, which is a full scan of the
+// tree.
+func (t *suffixTree[K, V]) Prefix(p K) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for k, v := range t.All() {
+			if bytes.HasPrefix([]byte(k), []byte(p)) && !yield(k, v) {
+				return
+			}
+		}
+	}
+}
+
+// PrefixBackward is Prefix in reverse, which is a full scan of the tree.
+func (t *suffixTree[K, V]) PrefixBackward(p K) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for k, v := range t.Backward() {
+			if bytes.HasPrefix([]byte(k), []byte(p)) && !yield(k, v) {
+				return
+			}
+		}
+	}
+}
+
+func (t *suffixTree[K, V]) Suffix(s K) iter.Seq2[K, V] {
+	if !t.labels {
+		return t.reversed(t.inner.Prefix(t.reverse(s)))
+	}
+	if len(s) == 0 {
+		return t.All()
+	}
+
+	// s itself and then the keys with more labels
+	return func(yield func(K, V) bool) {
+		rs := t.reverse(s)
+		if v, ok := t.inner.Search(rs); ok && !yield(s, v) {
+			return
+		}
+
+		for k, v := range t.inner.Prefix(K(append([]byte(rs), t.labelSep))) {
+			if !yield(t.reverse(k), v) {
+				return
+			}
+		}
+	}
+}
+
+func (t *suffixTree[K, V]) TopK(k uint) iter.Seq2[K, V] { return t.reversed(t.inner.TopK(k)) }
+
+func (t *suffixTree[K, V]) BottomK(k uint) iter.Seq2[K, V] { return t.reversed(t.inner.BottomK(k)) }
+
+// Range iterates over the keys between start and end in the order of the
+// reversed keys.
+func (t *suffixTree[K, V]) Range(start, end K) iter.Seq2[K, V] {
+	return t.reversed(t.inner.Range(t.reverse(start), t.reverse(end)))
+}
+
+// RangeWith iterates over the keys within the bounds of the options in the
+// order of the reversed keys.
+func (t *suffixTree[K, V]) RangeWith(opts RangeOptions[K]) iter.Seq2[K, V] {
+	opts.Start.key = t.reverse(opts.Start.key)
+	opts.End.key = t.reverse(opts.End.key)
+	return t.reversed(t.inner.RangeWith(opts))
+}
+
+func (t *suffixTree[K, V]) Size() int { return t.inner.Size() }
+
+func (t *suffixTree[K, V]) Stats() Stats { return t.inner.Stats() }
+
+func (t *suffixTree[K, V]) reversed(seq iter.Seq2[K, V]) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for k, v := range seq {
+			if !yield(t.reverse(k), v) {
+				return
+			}
+		}
+	}
+}
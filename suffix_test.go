@@ -0,0 +1,148 @@
+package art_test
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/Clement-Jean/go-art"
+	"golang.org/x/text/unicode/norm"
+)
+
+func TestSuffix(t *testing.T) {
+	tr := art.NewSuffixTree[string, int]()
+	hosts := []string{"example.com", "www.example.com", "badexample.com", "example.net", "api.example.com"}
+
+	for i, h := range hosts {
+		tr.Insert(h, i)
+	}
+
+	var got []string
+	for k, _ := range tr.Suffix(".example.com") {
+		got = append(got, k)
+	}
+
+	slices.Sort(got)
+	if expected := []string{"api.example.com", "www.example.com"}; !slices.Equal(expected, got) {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+
+	got = got[:0]
+	for k, _ := range tr.Suffix("example.com") {
+		got = append(got, k)
+	}
+
+	if len(got) != 4 {
+		t.Fatalf("expected 4 keys, got %v", got)
+	}
+
+	if v, ok := tr.Search("example.net"); !ok || v != 3 {
+		t.Fatalf("expected 3, got %d", v)
+	}
+}
+
+func TestSuffixLabels(t *testing.T) {
+	tr := art.NewSuffixTree[[]byte, int](art.WithLabelSeparator('.'))
+	hosts := []string{"example.com", "www.example.com", "badexample.com", "a.b.example.com", "example.net", "com"}
+
+	for i, h := range hosts {
+		tr.Insert([]byte(h), i)
+	}
+
+	tests := []struct {
+		suffix   string
+		expected []string
+	}{
+		{suffix: "example.com", expected: []string{"example.com", "a.b.example.com", "www.example.com"}},
+		{suffix: "b.example.com", expected: []string{"a.b.example.com"}},
+		{suffix: "com", expected: []string{"com", "badexample.com", "example.com", "a.b.example.com", "www.example.com"}},
+		{suffix: "le.com", expected: nil},
+		{suffix: "", expected: []string{"com", "badexample.com", "example.com", "a.b.example.com", "www.example.com", "example.net"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.suffix, func(t *testing.T) {
+			var got []string
+			for k, _ := range tr.Suffix([]byte(tt.suffix)) {
+				got = append(got, string(k))
+			}
+
+			if !slices.Equal(tt.expected, got) {
+				t.Fatalf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestSuffixWords(t *testing.T) {
+	words := loadTestFile("testdata/words.txt")
+	tr := art.NewSuffixTree[string, int]()
+
+	for i, w := range words {
+		tr.Insert(string(w), i)
+	}
+
+	for _, suffix := range []string{"ing", "tion", "zz", "q"} {
+		var expected []string
+		for _, w := range words {
+			if strings.HasSuffix(string(w), suffix) {
+				expected = append(expected, string(w))
+			}
+		}
+
+		var got []string
+		for k, _ := range tr.Suffix(suffix) {
+			got = append(got, k)
+		}
+
+		slices.Sort(expected)
+		expected = slices.Compact(expected)
+		slices.Sort(got)
+		if !slices.Equal(expected, got) {
+			t.Fatalf("%q: expected %d keys, got %d", suffix, len(expected), len(got))
+		}
+	}
+}
+
+func TestSuffixCaseFolding(t *testing.T) {
+	tr := art.NewSuffixTree[string, int](art.WithSimpleCaseFolding())
+	for i, k := range []string{"Café", "décafé", "naïve", "CAFE", "cafè"} {
+		tr.Insert(k, i)
+	}
+
+	if tr.Size() != 5 {
+		t.Fatalf("expected the accents to be kept apart, got size %d", tr.Size())
+	}
+
+	var got []string
+	for k, _ := range tr.Suffix("FÉ") {
+		got = append(got, k)
+	}
+
+	slices.Sort(got)
+	if expected := []string{"Café", "décafé"}; !slices.Equal(expected, got) {
+		t.Fatalf("expected %q, got %q", expected, got)
+	}
+
+	if v, ok := tr.Search("NAÏVE"); !ok || v != 2 {
+		t.Fatalf("expected naïve to be found, got %d", v)
+	}
+}
+
+func TestSuffixNormalization(t *testing.T) {
+	tr := art.NewSuffixTree[string, int](art.WithNormalization(norm.NFC))
+	tr.Insert("caf\u00e9", 1)
+	tr.Insert("tea", 2)
+
+	if v, ok := tr.Search("cafe\u0301"); !ok || v != 1 {
+		t.Fatalf("expected the NFD spelling to find café, got %d, %t", v, ok)
+	}
+
+	var got []string
+	for k := range tr.Suffix("e\u0301") {
+		got = append(got, k)
+	}
+	if expected := []string{"caf\u00e9"}; !slices.Equal(expected, got) {
+		t.Fatalf("expected %q, got %q", expected, got)
+	}
+}
@@ -0,0 +1,63 @@
+package art
+
+import (
+	"encoding/binary"
+	"time"
+)
+
+// NewTimeTree returns a tree ordering the times by instant with nanosecond
+// precision. The keys are restored in UTC.
+func NewTimeTree[V any](opts ...Option) Tree[time.Time, V] {
+	return NewCompoundTree[time.Time, V](TimeBinaryKey{}, opts...)
+}
+
+// NewDurationTree returns a tree ordering durations.
+func NewDurationTree[V any](opts ...Option) Tree[time.Duration, V] {
+	return NewCompoundTree[time.Duration, V](DurationBinaryKey{}, opts...)
+}
+
+// TimeBinaryKey orders times by instant. The seconds since the Unix epoch are
+// encoded like SignedBinaryKey and followed by the nanoseconds, which covers
+// the zero time. The monotonic clock reading is dropped.
+//
+// Times are restored in Location, or in UTC when Location is nil. Two times
+// of the same instant in different locations are the same key.
+type TimeBinaryKey struct {
+	Location *time.Location
+}
+
+func (tbk TimeBinaryKey) Transform(t time.Time) ([]byte, []byte) {
+	var sbk SignedBinaryKey[int64]
+
+	_, sec := sbk.Transform(t.Unix())
+	b := binary.BigEndian.AppendUint32(sec, uint32(t.Nanosecond()))
+	return b, b
+}
+func (tbk TimeBinaryKey) Restore(b []byte) time.Time {
+	var sbk SignedBinaryKey[int64]
+
+	t := time.Unix(sbk.Restore(b[:8]), int64(binary.BigEndian.Uint32(b[8:])))
+	if tbk.Location == nil {
+		return t.UTC()
+	}
+	return t.In(tbk.Location)
+}
+
+var _ BinaryComparableKey[time.Time] = TimeBinaryKey{}
+
+// DurationBinaryKey orders durations like SignedBinaryKey.
+type DurationBinaryKey struct{}
+
+func (dbk DurationBinaryKey) Transform(d time.Duration) ([]byte, []byte) {
+	var sbk SignedBinaryKey[int64]
+	return sbk.Transform(int64(d))
+}
+func (dbk DurationBinaryKey) Restore(b []byte) time.Duration {
+	var sbk SignedBinaryKey[int64]
+	return time.Duration(sbk.Restore(b))
+}
+
+var _ BinaryComparableKey[time.Duration] = DurationBinaryKey{}
+
+func (TimeBinaryKey) fixedSize()     {}
+func (DurationBinaryKey) fixedSize() {}
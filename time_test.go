@@ -0,0 +1,124 @@
+package art_test
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/Clement-Jean/go-art"
+)
+
+func TestTimeTree(t *testing.T) {
+	base := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+	paris, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Skip("no time zone database")
+	}
+
+	times := []time.Time{
+		base.Add(time.Nanosecond),
+		base.Add(-time.Hour).In(paris),
+		{},
+		base,
+		time.Date(1900, 1, 1, 0, 0, 0, 999, time.UTC),
+		base.Add(time.Nanosecond).In(paris), // same instant as the first one
+	}
+
+	tr := art.NewTimeTree[int]()
+	for i, tm := range times {
+		tr.Insert(tm, i)
+	}
+
+	if tr.Size() != 5 {
+		t.Fatalf("expected size 5, got %d", tr.Size())
+	}
+
+	var got []time.Time
+	for k, _ := range tr.All() {
+		got = append(got, k)
+	}
+
+	expected := []time.Time{
+		{},
+		time.Date(1900, 1, 1, 0, 0, 0, 999, time.UTC),
+		base.Add(-time.Hour),
+		base,
+		base.Add(time.Nanosecond),
+	}
+	if !slices.Equal(expected, got) {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+
+	if !got[0].IsZero() {
+		t.Fatalf("expected the zero time, got %v", got[0])
+	}
+
+	if v, ok := tr.Search(base.Add(time.Nanosecond)); !ok || v != 5 {
+		t.Fatalf("expected 5, got %d", v)
+	}
+}
+
+func TestTimeMonotonic(t *testing.T) {
+	tr := art.NewTimeTree[int]()
+
+	now := time.Now()
+	tr.Insert(now, 1)
+
+	if _, ok := tr.Search(now.Round(0)); !ok {
+		t.Fatal("expected the time without monotonic reading to be found")
+	}
+
+	k, _, _ := tr.Minimum()
+	if !k.Equal(now) || k.Location() != time.UTC {
+		t.Fatalf("expected %v in UTC, got %v", now, k)
+	}
+}
+
+func TestTimeLocation(t *testing.T) {
+	tokyo := time.FixedZone("JST", 9*60*60)
+	tr := art.NewCompoundTree[time.Time, int](art.TimeBinaryKey{Location: tokyo})
+
+	tr.Insert(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), 1)
+
+	k, _, _ := tr.Minimum()
+	if k.Location() != tokyo || k.Hour() != 9 {
+		t.Fatalf("expected 09:00 JST, got %v", k)
+	}
+}
+
+func TestTimeDescending(t *testing.T) {
+	base := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+	desc := art.Desc[time.Time]{Key: art.TimeBinaryKey{}}
+	tr := art.NewCompoundTree[time.Time, int](desc)
+
+	for i := range 3 {
+		tr.Insert(base.Add(time.Duration(i)*time.Second), i)
+	}
+
+	var got []int
+	for _, v := range tr.All() {
+		got = append(got, v)
+	}
+
+	if expected := []int{2, 1, 0}; !slices.Equal(expected, got) {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+}
+
+func TestDurationTree(t *testing.T) {
+	tr := art.NewDurationTree[int]()
+	durations := []time.Duration{time.Second, -time.Minute, 0, time.Nanosecond, time.Hour}
+
+	for i, d := range durations {
+		tr.Insert(d, i)
+	}
+
+	var got []time.Duration
+	for k, _ := range tr.Range(0, time.Minute) {
+		got = append(got, k)
+	}
+
+	if expected := []time.Duration{0, time.Nanosecond, time.Second}; !slices.Equal(expected, got) {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+}
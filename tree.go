@@ -3,6 +3,8 @@ package art
 import (
 	"bytes"
 	"iter"
+	"slices"
+	"sync/atomic"
 	"unsafe"
 )
 
@@ -26,11 +28,19 @@ type Tree[K nodeKey, V any] interface {
 	All() iter.Seq2[K, V]
 	Backward() iter.Seq2[K, V]
 	Prefix(K) iter.Seq2[K, V]
+	PrefixBackward(K) iter.Seq2[K, V]
 	TopK(uint) iter.Seq2[K, V]
 	BottomK(uint) iter.Seq2[K, V]
 	Range(K, K) iter.Seq2[K, V]
 
+	// RangeWith iterates over the keys within the bounds of the options,
+	// in ascending or descending order.
+	RangeWith(RangeOptions[K]) iter.Seq2[K, V]
+
 	Size() int
+
+	// Stats walks the tree and reports its shape and memory footprint.
+	Stats() Stats
 }
 
 func longestCommonPrefix(key, other []byte, depth int) int {
@@ -46,7 +56,9 @@ func longestCommonPrefix(key, other []byte, depth int) int {
 	return idx - depth
 }
 
-func prefixMismatch[V any, L nodeLeaf[V]](n nodeRef, key []byte, depth int) int {
+// prefixMismatch returns the length of the common part of the compressed path
+// of n and key, loading the path from a leaf past maxPrefixLen.
+func prefixMismatch[V any, L nodeLeaf[V]](n nodeRef, key []byte, depth int, overflows *atomic.Int64) int {
 	node := n.node()
 	maxCmp := min(int(min(maxPrefixLen, node.prefixLen)), len(key)-depth)
 
@@ -58,6 +70,7 @@ func prefixMismatch[V any, L nodeLeaf[V]](n nodeRef, key []byte, depth int) int
 	}
 
 	if node.prefixLen > maxPrefixLen {
+		overflows.Add(1)
 		leaf := (L)(minimum[V](n))
 		leafKey := leaf.getTransformKey()
 
@@ -331,236 +344,199 @@ func bottomK[K nodeKey, V any](t Tree[K, V], k uint) iter.Seq2[K, V] {
 	}
 }
 
-func lowestCommonParent[V any, L nodeLeaf[V]](root nodeRef, prefix []byte) nodeRef {
-	var q []nodeRef
-
+// prefixRoot descends to the node whose subtree holds exactly the keys
+// starting with prefix. The prefix can end in the middle of a compressed path.
+func prefixRoot[V any, L nodeLeaf[V]](root nodeRef, prefix []byte, overflows *atomic.Int64) nodeRef {
+	n := root
 	depth := 0
-	q = append(q, root)
-	for len(q) != 0 {
-		n := q[len(q)-1]
-		q = q[:len(q)-1]
-
-		idx := prefixMismatch[V, L](n, prefix, depth)
-		if idx == 0 { // no match
-			continue
+
+	for n.pointer != nil {
+		if n.tag == nodeKindLeaf {
+			if !bytes.HasPrefix((L)(n.pointer).getTransformKey(), prefix) {
+				return nodeRef{}
+			}
+			return n
 		}
 
-		if idx < min(len(prefix)-depth, maxPrefixLen) {
-			root = n
-			break
+		if depth == len(prefix) {
+			return n
 		}
 
-		switch n.tag {
-		case nodeKind4:
-			n4 := (*node4)(n.pointer)
+		node := n.node()
+		if prefixLen := int(node.prefixLen); prefixLen != 0 {
+			cmpLen := min(prefixLen, len(prefix)-depth)
+			nodePrefix := node.prefix[:min(cmpLen, maxPrefixLen)]
 
-			for i := int(n4.childrenLen) - 1; i >= 0; i-- {
-				if n4.children[i].tag == nodeKindLeaf {
-					continue
-				}
-				q = append(q, n4.children[i])
+			if cmpLen > maxPrefixLen {
+				overflows.Add(1)
+				nodePrefix = fullPrefix[V, L](n, depth)[:cmpLen]
 			}
 
-		case nodeKind16:
-			n16 := (*node16)(n.pointer)
-
-			for i := int(n16.childrenLen) - 1; i >= 0; i-- {
-				if n16.children[i].tag == nodeKindLeaf {
-					continue
-				}
-				q = append(q, n16.children[i])
+			if !bytes.Equal(nodePrefix, prefix[depth:depth+cmpLen]) {
+				return nodeRef{}
 			}
 
-		case nodeKind48:
-			n48 := (*node48)(n.pointer)
-
-			for i := 255; i >= 0; i-- {
-				idx := n48.keys[i]
-				if idx == 0 || n48.children[i].tag == nodeKindLeaf {
-					continue
-				}
-				q = append(q, n48.children[idx-1])
-			}
-
-		case nodeKind256:
-			n256 := (*node256)(n.pointer)
-
-			for i := 255; i >= 0; i-- {
-				if n256.children[i].pointer == nil || n256.children[i].tag == nodeKindLeaf {
-					continue
-				}
-
-				q = append(q, n256.children[i])
+			if depth+prefixLen >= len(prefix) {
+				return n
 			}
+			depth += prefixLen
+		}
 
-		default:
-			panic("shouldn't be possible!")
+		child := n.findChild(prefix[depth])
+		if child == nil {
+			return nodeRef{}
 		}
 
-		depth += idx + 1
+		n = *child
+		depth++
 	}
 
-	return root
+	return nodeRef{}
 }
 
-func filter[K nodeKey, V any](root nodeRef, predicate func(K, V) bool, restore func(unsafe.Pointer) (K, V)) iter.Seq2[K, V] {
-	return func(yield func(K, V) bool) {
-		if root.pointer == nil {
-			return
-		}
-
-		var q []nodeRef
-
-		q = append(q, root)
-		for len(q) != 0 {
-			n := q[len(q)-1]
-			q = q[:len(q)-1]
-
-			if n.tag == nodeKindLeaf {
-				k, v := restore(n.pointer)
-
-				if predicate(k, v) {
-					if !yield(k, v) {
-						return
-					}
-				}
-				continue
-			}
-
-			switch n.tag {
-			case nodeKind4:
-				n4 := (*node4)(n.pointer)
-
-				for i := int(n4.childrenLen) - 1; i >= 0; i-- {
-					q = append(q, n4.children[i])
-				}
-
-			case nodeKind16:
-				n16 := (*node16)(n.pointer)
-
-				for i := int(n16.childrenLen) - 1; i >= 0; i-- {
-					q = append(q, n16.children[i])
-				}
-
-			case nodeKind48:
-				n48 := (*node48)(n.pointer)
-
-				for i := 255; i >= 0; i-- {
-					idx := n48.keys[i]
-					if idx == 0 {
-						continue
-					}
-					q = append(q, n48.children[idx-1])
-				}
-
-			case nodeKind256:
-				n256 := (*node256)(n.pointer)
-
-				for i := 255; i >= 0; i-- {
-					if n256.children[i].pointer == nil {
-						continue
-					}
-					q = append(q, n256.children[i])
-				}
-
-			default:
-				panic("shouldn't be possible!")
-			}
-		}
+// fullPrefix returns the compressed path of n, loading it from a leaf when it
+// doesn't fit in the node.
+func fullPrefix[V any, L nodeLeaf[V]](n nodeRef, depth int) []byte {
+	node := n.node()
+	if node.prefixLen <= maxPrefixLen {
+		return node.prefix[:node.prefixLen]
 	}
+
+	leaf := (L)(minimum[V](n))
+	return leaf.getTransformKey()[depth : depth+int(node.prefixLen)]
 }
 
+// rangeScan iterates over the leaves whose transformed key is within the
+// bounds. It only descends into the children which can hold such a leaf: while
+// a path still follows the start (resp. end) bound, the children below (resp.
+// above) the bound's byte are skipped.
 func rangeScan[K nodeKey, V any, L nodeLeaf[V]](
 	root nodeRef,
-	start, end []byte,
-	transformStart, transformEnd []byte,
+	bounds scanBounds,
 	restore func(unsafe.Pointer) (K, V),
+	overflows *atomic.Int64,
 ) iter.Seq2[K, V] {
-	idx := longestCommonPrefix(transformStart, transformEnd, 0)
-
-	var search []byte
-	if idx != 0 {
-		search = transformStart[:idx]
+	type frame struct {
+		ref    nodeRef
+		depth  int
+		lo, hi bool // still following the start/end bound
 	}
 
+	start, end := bounds.start, bounds.end
+
 	return func(yield func(K, V) bool) {
-		var q []nodeRef
+		if root.pointer == nil {
+			return
+		}
 
-		depth := 0
-		q = append(q, root)
+		// whether a leaf or a subtree out of the bounds ends the iteration
+		// or is simply skipped depends on the direction.
+		stopBeforeStart, stopAfterEnd := bounds.reverse, !bounds.reverse
+
+		count := 0
+		q := []frame{{ref: root, lo: bounds.startKind != unbounded, hi: bounds.endKind != unbounded}}
 		for len(q) != 0 {
-			n := q[len(q)-1]
+			f := q[len(q)-1]
 			q = q[:len(q)-1]
 
-			if n.tag == nodeKindLeaf {
-				leaf := (L)(n.pointer)
+			if f.ref.tag == nodeKindLeaf {
+				key := (L)(f.ref.pointer).getTransformKey()
 
-				if bytes.Compare(leaf.getKey(), start) < 0 {
-					continue
+				if f.lo {
+					if cmp := bytes.Compare(key, start); cmp < 0 || (cmp == 0 && bounds.startKind == exclusive) {
+						if stopBeforeStart {
+							return // every remaining leaf is smaller
+						}
+						continue
+					}
 				}
 
-				if bytes.Compare(leaf.getKey(), end) > 0 {
-					break // no need to go further
+				if f.hi {
+					if cmp := bytes.Compare(key, end); cmp > 0 || (cmp == 0 && bounds.endKind == exclusive) {
+						if stopAfterEnd {
+							return // every remaining leaf is greater
+						}
+						continue
+					}
 				}
 
-				k, v := restore(n.pointer)
+				k, v := restore(f.ref.pointer)
 				if !yield(k, v) {
 					return
 				}
-				continue
-			}
-
-			node := n.node()
 
-			if node.prefixLen > 0 && depth < len(search) {
-				nodeKey := unsafe.Slice(&node.prefix[0], min(maxPrefixLen, node.prefixLen))
-				idx := longestCommonPrefix(nodeKey, search[depth:depth+min(len(search)-depth, maxPrefixLen)], 0)
-				if idx == 0 { // no match
-					continue
+				if count++; count == bounds.limit {
+					return
 				}
+				continue
 			}
 
-			switch n.tag {
-			case nodeKind4:
-				n4 := (*node4)(n.pointer)
-
-				for i := int(n4.childrenLen) - 1; i >= 0; i-- {
-					q = append(q, n4.children[i])
+			depth := f.depth
+			if prefixLen := int(f.ref.node().prefixLen); prefixLen != 0 && (f.lo || f.hi) {
+				if prefixLen > maxPrefixLen {
+					overflows.Add(1)
 				}
-
-			case nodeKind16:
-				n16 := (*node16)(n.pointer)
-
-				for i := int(n16.childrenLen) - 1; i >= 0; i-- {
-					q = append(q, n16.children[i])
+				prefix := fullPrefix[V, L](f.ref, depth)
+
+				if f.lo {
+					bound := start[min(depth, len(start)):min(depth+prefixLen, len(start))]
+					switch bytes.Compare(prefix, bound) {
+					case -1: // the whole subtree is before start
+						if stopBeforeStart {
+							return
+						}
+						continue
+					case 1:
+						f.lo = false
+					}
 				}
 
-			case nodeKind48:
-				n48 := (*node48)(n.pointer)
-
-				for i := 255; i >= 0; i-- {
-					idx := n48.keys[i]
-					if idx == 0 {
+				if f.hi {
+					bound := end[min(depth, len(end)):min(depth+prefixLen, len(end))]
+					switch bytes.Compare(prefix, bound) {
+					case 1: // the whole subtree is after end
+						if stopAfterEnd {
+							return
+						}
 						continue
+					case -1:
+						f.hi = false
 					}
-					q = append(q, n48.children[idx-1])
 				}
 
-			case nodeKind256:
-				n256 := (*node256)(n.pointer)
+				depth += prefixLen
+			}
 
-				for i := 255; i >= 0; i-- {
-					if n256.children[i].pointer == nil {
-						continue
+			loByte, hiByte := byte(0), byte(255)
+			if f.lo {
+				if depth < len(start) {
+					loByte = start[depth]
+				} else {
+					f.lo = false // every key below extends start
+				}
+			}
+			if f.hi {
+				if depth >= len(end) { // every key below extends end
+					if stopAfterEnd {
+						return
 					}
-					q = append(q, n256.children[i])
+					continue
 				}
-
-			default:
-				panic("shouldn't be possible!")
+				hiByte = end[depth]
 			}
 
-			depth += int(node.prefixLen) + 1
+			first := len(q)
+			f.ref.eachChild(loByte, hiByte, func(b byte, child nodeRef) {
+				q = append(q, frame{
+					ref:   child,
+					depth: depth + 1,
+					lo:    f.lo && b == loByte,
+					hi:    f.hi && b == hiByte,
+				})
+			})
+			if !bounds.reverse {
+				slices.Reverse(q[first:])
+			}
 		}
 	}
 }
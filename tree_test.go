@@ -396,6 +396,26 @@ func BenchmarkHSKCollate(b *testing.B) {
 	}
 }
 
+func BenchmarkUnsignedRange(b *testing.B) {
+	tree := art.NewUnsignedBinaryTree[uint64, uint64]()
+
+	for i := range uint64(1_000_000) {
+		tree.Insert(i*7, i)
+	}
+
+	for _, width := range []uint64{10, 1_000, 100_000} {
+		b.Run(fmt.Sprintf("range_width_%d", width), func(b *testing.B) {
+			start := uint64(0)
+
+			for b.Loop() {
+				for range tree.Range(start, start+width) {
+				}
+				start = (start + 7_919) % 7_000_000
+			}
+		})
+	}
+}
+
 func FuzzAlphaTreeInsert(f *testing.F) {
 	words := loadTestFile("testdata/words.txt")
 
@@ -408,3 +428,47 @@ func FuzzAlphaTreeInsert(f *testing.F) {
 		tree.Insert(a, b)
 	})
 }
+
+func BenchmarkUUIDFixed(b *testing.B) {
+	var uuids []UUID
+	for _, line := range loadTestFile("testdata/uuid.txt") {
+		uuids = append(uuids, parseUUID(line))
+	}
+	sizes := []int{100, 1_000, 10_000, 100_000}
+
+	for _, op := range []testOp{insert, search} {
+		tree := art.NewUUIDTree[UUID, int]()
+
+		if op == search {
+			for i, u := range uuids {
+				tree.Insert(u, i)
+			}
+		}
+
+		switch op {
+		case insert:
+			for _, size := range sizes {
+				b.Run(fmt.Sprintf("insert_size_%d", size), func(b *testing.B) {
+					i := 0
+
+					for b.Loop() {
+						tree.Insert(uuids[i], i)
+						i = (i + 1) % size
+					}
+				})
+			}
+
+		case search:
+			for _, size := range sizes {
+				b.Run(fmt.Sprintf("search_size_%d", size), func(b *testing.B) {
+					i := 0
+
+					for b.Loop() {
+						tree.Search(uuids[i])
+						i = (i + 1) % size
+					}
+				})
+			}
+		}
+	}
+}
@@ -1,16 +1,17 @@
-// Code generated by "go run cmd/go-art/main.go > trees.go"; DO NOT EDIT.
+// Code generated by "go run ./cmd/go-art > trees.go"; DO NOT EDIT.
 
 package art
 
 import (
 	"bytes"
 	"iter"
+	"sync/atomic"
 	"unsafe"
 )
 
 type alphaLeafNode[V any] struct {
+	value V // first, so that a struct{} value takes no space
 	key   *byte
-	value V
 	len   uint32
 }
 
@@ -19,17 +20,18 @@ func (n *alphaLeafNode[V]) getTransformKey() []byte { return unsafe.Slice(n.key,
 
 type alphaSortedTree[K chars, V any] struct {
 	root nodeRef
-	bck  AlphabeticalOrderKey[K]
+	alphaCodec[K]
 	size int
+
+	overflows atomic.Int64
+	sums      summaries[V]
 }
 
 func (t *alphaSortedTree[K, V]) restoreKey(ptr unsafe.Pointer) (K, V) {
 	l := (*alphaLeafNode[V])(ptr)
 	keyS := l.getKey()
-
-	keyS = keyS[:len(keyS)-1] // drop end byte
-
-	return t.bck.Restore(keyS), l.value
+	t.opts.checker.check(ptr, keyS)
+	return t.decodeKey(keyS), l.value
 }
 
 func (t *alphaSortedTree[K, V]) All() iter.Seq2[K, V] {
@@ -49,19 +51,21 @@ func (t *alphaSortedTree[K, V]) Delete(key K) bool {
 		return false
 	}
 
-	_, keyS := t.bck.Transform(key)
-
-	keyS = append(keyS, '\x00')
+	keyS := t.encodeKey(key)
 
 	ref := &t.root
 	n := *ref
 	depth := 0
 
+	var path []pathNode // the inner nodes above the leaf, for the summaries
+
 	for n.pointer != nil {
 		if n.tag == nodeKindLeaf {
 			leaf := (*alphaLeafNode[V])(n.pointer)
+			t.opts.checker.check(n.pointer, leaf.getKey())
 
 			if bytes.Equal(leaf.getKey(), keyS) {
+				t.opts.checker.forget(n.pointer)
 				*ref = nodeRef{}
 				t.size--
 				return true
@@ -84,13 +88,21 @@ func (t *alphaSortedTree[K, V]) Delete(key K) bool {
 		if child == nil {
 			return false
 		}
+		if t.sums != nil {
+			path = append(path, pathNode{ref, *ref})
+		}
 
 		if child.tag == nodeKindLeaf {
 			leaf := (*alphaLeafNode[V])(child.pointer)
+			t.opts.checker.check(child.pointer, leaf.getKey())
 
 			if bytes.Equal(leaf.getKey(), keyS) {
+				t.opts.checker.forget(child.pointer)
 				ref.deleteChild(keyS[depth])
 				t.size--
+				if t.sums != nil {
+					t.sums.remove(path, leaf.value)
+				}
 				return true
 			}
 
@@ -105,16 +117,18 @@ func (t *alphaSortedTree[K, V]) Delete(key K) bool {
 }
 
 func (t *alphaSortedTree[K, V]) Insert(key K, val V) {
-	_, keyS := t.bck.Transform(key)
-
-	keyS = append(keyS, '\x00')
+	keyS := t.encodeKey(key)
 
 	createLeaf := func() unsafe.Pointer {
-		return unsafe.Pointer(&alphaLeafNode[V]{
-			key:   unsafe.SliceData(keyS),
+
+		leaf := unsafe.Pointer(&alphaLeafNode[V]{
 			value: val,
+			key:   unsafe.SliceData(keyS),
 			len:   uint32(len(keyS)),
 		})
+
+		t.opts.checker.record(leaf, keyS)
+		return leaf
 	}
 
 	if t.root.pointer == nil {
@@ -127,11 +141,13 @@ func (t *alphaSortedTree[K, V]) Insert(key K, val V) {
 	n := *ref
 	depth := 0
 
+	var path []pathNode // the inner nodes above the leaf, for the summaries
+
 	for ref.pointer != nil {
 		if ref.tag != nodeKindLeaf {
 			node := ref.node()
 			if node.prefixLen != 0 {
-				prefixDiff := prefixMismatch[V, *alphaLeafNode[V]](n, keyS, depth)
+				prefixDiff := prefixMismatch[V, *alphaLeafNode[V]](n, keyS, depth, &t.overflows)
 
 				if prefixDiff >= int(node.prefixLen) {
 					depth += int(node.prefixLen)
@@ -152,6 +168,7 @@ func (t *alphaSortedTree[K, V]) Insert(key K, val V) {
 					copy(node.prefix[:], node.prefix[loLimit:])
 				} else {
 					node.prefixLen -= uint32(prefixDiff + 1)
+					t.overflows.Add(1) // loading the rest of the path
 					leafMin := (*alphaLeafNode[V])(minimum[V](n))
 					leafKey := leafMin.getTransformKey()
 
@@ -161,10 +178,18 @@ func (t *alphaSortedTree[K, V]) Insert(key K, val V) {
 				}
 
 				if depth+prefixDiff >= len(keyS) {
+					if t.sums != nil {
+						t.sums.node(*ref)
+					}
 					return
 				}
 				leafRef := nodeRef{pointer: createLeaf(), tag: nodeKindLeaf}
 				newNode.addChild(ref, keyS[depth+prefixDiff], leafRef)
+				t.size++
+				if t.sums != nil {
+					t.sums.node(*ref)
+					t.sums.insert(path, val)
+				}
 				return
 			}
 
@@ -172,6 +197,9 @@ func (t *alphaSortedTree[K, V]) Insert(key K, val V) {
 			if depth >= len(keyS) {
 				return
 			}
+			if t.sums != nil {
+				path = append(path, pathNode{ref, *ref})
+			}
 
 			child := ref.findChild(keyS[depth])
 			if child != nil {
@@ -184,13 +212,21 @@ func (t *alphaSortedTree[K, V]) Insert(key K, val V) {
 			leafRef := nodeRef{pointer: createLeaf(), tag: nodeKindLeaf}
 			ref.addChild(keyS[depth], leafRef)
 			t.size++
+			if t.sums != nil {
+				t.sums.insert(path, val)
+			}
 			return
 		}
 
 		nl := (*alphaLeafNode[V])(ref.pointer)
+		t.opts.checker.check(ref.pointer, nl.getKey())
 
 		if bytes.Equal(keyS, nl.getKey()) {
+			old := nl.value
 			nl.value = val
+			if t.sums != nil {
+				t.sums.replace(path, old, val)
+			}
 			return
 		}
 
@@ -214,6 +250,12 @@ func (t *alphaSortedTree[K, V]) Insert(key K, val V) {
 			newNode.addChild(ref, keyS[splitPrefix], leafRef)
 		}
 		t.size++
+		if t.sums != nil {
+			t.sums.node(*ref)
+			if splitPrefix < len(keyS) {
+				t.sums.insert(path, val)
+			}
+		}
 		return
 	}
 }
@@ -246,47 +288,34 @@ func (t *alphaSortedTree[K, V]) Minimum() (K, V, bool) {
 
 func (t *alphaSortedTree[K, V]) Prefix(p K) iter.Seq2[K, V] {
 
-	if len(p) == 0 {
-		return t.All()
-	}
-
-	root := t.root
-	if t.root.pointer != nil {
-		root = lowestCommonParent[V, *alphaLeafNode[V]](root, []byte(p))
-	}
-
-	hasPrefix := func(k K, v V) bool { return bytes.HasPrefix([]byte(k), []byte(p)) }
-	return filter(root, hasPrefix, t.restoreKey)
+	root := prefixRoot[V, *alphaLeafNode[V]](t.root, t.encodePrefix(p), &t.overflows)
+	return all(root, t.restoreKey)
 
 }
 
-func (t *alphaSortedTree[K, V]) Range(start, end K) iter.Seq2[K, V] {
-
-	if len(end) == 0 {
-		end, _ = t.restoreKey(maximum[V](t.root))
-	}
-
-	if bytes.Compare([]byte(start), []byte(end)) > 0 { // start > end
-		// IDEA: maybe do the iteration in reverse instead?
-		start, end = end, start
-	}
+func (t *alphaSortedTree[K, V]) PrefixBackward(p K) iter.Seq2[K, V] {
 
-	startKey := []byte(start)
+	root := prefixRoot[V, *alphaLeafNode[V]](t.root, t.encodePrefix(p), &t.overflows)
+	return backward(root, t.restoreKey)
 
-	startKey = append(startKey, '\x00')
+}
 
-	endKey := []byte(end)
+func (t *alphaSortedTree[K, V]) Range(start, end K) iter.Seq2[K, V] {
+	startKey := t.encodeKey(start)
+	endKey := t.encodeKey(end)
 
-	endKey = append(endKey, '\x00')
+	bounds := rangeBounds(startKey, endKey, len(end) == 0)
 
-	return rangeScan[K, V, *alphaLeafNode[V]](t.root, startKey, endKey, startKey, endKey, t.restoreKey)
+	return rangeScan[K, V, *alphaLeafNode[V]](t.root, bounds, t.restoreKey, &t.overflows)
+}
 
+func (t *alphaSortedTree[K, V]) RangeWith(opts RangeOptions[K]) iter.Seq2[K, V] {
+	bounds := newScanBounds(opts, t.encodeKey)
+	return rangeScan[K, V, *alphaLeafNode[V]](t.root, bounds, t.restoreKey, &t.overflows)
 }
 
 func (t *alphaSortedTree[K, V]) Search(key K) (V, bool) {
-	_, keyS := t.bck.Transform(key)
-
-	keyS = append(keyS, '\x00')
+	keyS := t.encodeKey(key)
 
 	var notFound V
 
@@ -351,6 +380,7 @@ func (t *alphaSortedTree[K, V]) Search(key K) (V, bool) {
 		}
 
 		leaf := (*alphaLeafNode[V])(n.pointer)
+		t.opts.checker.check(n.pointer, leaf.getKey())
 
 		if bytes.Equal(leaf.getKey(), keyS) {
 			return leaf.value, true
@@ -367,9 +397,34 @@ func (t *alphaSortedTree[K, V]) TopK(k uint) iter.Seq2[K, V] {
 
 func (t *alphaSortedTree[K, V]) Size() int { return t.size }
 
+func (t *alphaSortedTree[K, V]) rootRef() nodeRef { return t.root }
+
+func (t *alphaSortedTree[K, V]) setSummaries(s summaries[V]) {
+	if t.sums != nil {
+		panic("art: the tree already has an AggregateTree")
+	}
+	t.sums = s
+}
+
+func (t *alphaSortedTree[K, V]) leafKey(ptr unsafe.Pointer) []byte {
+	return (*alphaLeafNode[V])(ptr).getTransformKey()
+}
+
+func (t *alphaSortedTree[K, V]) leafValue(ptr unsafe.Pointer) V {
+	return (*alphaLeafNode[V])(ptr).value
+}
+
+// Stats walks the tree and reports its shape and memory footprint.
+func (t *alphaSortedTree[K, V]) Stats() Stats {
+	s := stats[V, *alphaLeafNode[V]](t.root, unsafe.Sizeof(alphaLeafNode[V]{}))
+	s.OverflowLookups = int(t.overflows.Load())
+
+	return s
+}
+
 type unsignedLeafNode[V any] struct {
+	value V // first, so that a struct{} value takes no space
 	key   *byte
-	value V
 	len   uint32
 }
 
@@ -378,15 +433,18 @@ func (n *unsignedLeafNode[V]) getTransformKey() []byte { return unsafe.Slice(n.k
 
 type unsignedSortedTree[K uints, V any] struct {
 	root nodeRef
-	bck  UnsignedBinaryKey[K]
+	unsignedCodec[K]
 	size int
+
+	overflows atomic.Int64
+	sums      summaries[V]
 }
 
 func (t *unsignedSortedTree[K, V]) restoreKey(ptr unsafe.Pointer) (K, V) {
 	l := (*unsignedLeafNode[V])(ptr)
 	keyS := l.getKey()
-
-	return t.bck.Restore(keyS), l.value
+	t.opts.checker.check(ptr, keyS)
+	return t.decodeKey(keyS), l.value
 }
 
 func (t *unsignedSortedTree[K, V]) All() iter.Seq2[K, V] {
@@ -406,17 +464,21 @@ func (t *unsignedSortedTree[K, V]) Delete(key K) bool {
 		return false
 	}
 
-	_, keyS := t.bck.Transform(key)
+	keyS := t.encodeKey(key)
 
 	ref := &t.root
 	n := *ref
 	depth := 0
 
+	var path []pathNode // the inner nodes above the leaf, for the summaries
+
 	for n.pointer != nil {
 		if n.tag == nodeKindLeaf {
 			leaf := (*unsignedLeafNode[V])(n.pointer)
+			t.opts.checker.check(n.pointer, leaf.getKey())
 
 			if bytes.Equal(leaf.getKey(), keyS) {
+				t.opts.checker.forget(n.pointer)
 				*ref = nodeRef{}
 				t.size--
 				return true
@@ -439,13 +501,21 @@ func (t *unsignedSortedTree[K, V]) Delete(key K) bool {
 		if child == nil {
 			return false
 		}
+		if t.sums != nil {
+			path = append(path, pathNode{ref, *ref})
+		}
 
 		if child.tag == nodeKindLeaf {
 			leaf := (*unsignedLeafNode[V])(child.pointer)
+			t.opts.checker.check(child.pointer, leaf.getKey())
 
 			if bytes.Equal(leaf.getKey(), keyS) {
+				t.opts.checker.forget(child.pointer)
 				ref.deleteChild(keyS[depth])
 				t.size--
+				if t.sums != nil {
+					t.sums.remove(path, leaf.value)
+				}
 				return true
 			}
 
@@ -460,14 +530,18 @@ func (t *unsignedSortedTree[K, V]) Delete(key K) bool {
 }
 
 func (t *unsignedSortedTree[K, V]) Insert(key K, val V) {
-	_, keyS := t.bck.Transform(key)
+	keyS := t.encodeKey(key)
 
 	createLeaf := func() unsafe.Pointer {
-		return unsafe.Pointer(&unsignedLeafNode[V]{
-			key:   unsafe.SliceData(keyS),
+
+		leaf := unsafe.Pointer(&unsignedLeafNode[V]{
 			value: val,
+			key:   unsafe.SliceData(keyS),
 			len:   uint32(len(keyS)),
 		})
+
+		t.opts.checker.record(leaf, keyS)
+		return leaf
 	}
 
 	if t.root.pointer == nil {
@@ -480,11 +554,13 @@ func (t *unsignedSortedTree[K, V]) Insert(key K, val V) {
 	n := *ref
 	depth := 0
 
+	var path []pathNode // the inner nodes above the leaf, for the summaries
+
 	for ref.pointer != nil {
 		if ref.tag != nodeKindLeaf {
 			node := ref.node()
 			if node.prefixLen != 0 {
-				prefixDiff := prefixMismatch[V, *unsignedLeafNode[V]](n, keyS, depth)
+				prefixDiff := prefixMismatch[V, *unsignedLeafNode[V]](n, keyS, depth, &t.overflows)
 
 				if prefixDiff >= int(node.prefixLen) {
 					depth += int(node.prefixLen)
@@ -505,6 +581,7 @@ func (t *unsignedSortedTree[K, V]) Insert(key K, val V) {
 					copy(node.prefix[:], node.prefix[loLimit:])
 				} else {
 					node.prefixLen -= uint32(prefixDiff + 1)
+					t.overflows.Add(1) // loading the rest of the path
 					leafMin := (*unsignedLeafNode[V])(minimum[V](n))
 					leafKey := leafMin.getTransformKey()
 
@@ -514,10 +591,18 @@ func (t *unsignedSortedTree[K, V]) Insert(key K, val V) {
 				}
 
 				if depth+prefixDiff >= len(keyS) {
+					if t.sums != nil {
+						t.sums.node(*ref)
+					}
 					return
 				}
 				leafRef := nodeRef{pointer: createLeaf(), tag: nodeKindLeaf}
 				newNode.addChild(ref, keyS[depth+prefixDiff], leafRef)
+				t.size++
+				if t.sums != nil {
+					t.sums.node(*ref)
+					t.sums.insert(path, val)
+				}
 				return
 			}
 
@@ -525,6 +610,9 @@ func (t *unsignedSortedTree[K, V]) Insert(key K, val V) {
 			if depth >= len(keyS) {
 				return
 			}
+			if t.sums != nil {
+				path = append(path, pathNode{ref, *ref})
+			}
 
 			child := ref.findChild(keyS[depth])
 			if child != nil {
@@ -537,13 +625,21 @@ func (t *unsignedSortedTree[K, V]) Insert(key K, val V) {
 			leafRef := nodeRef{pointer: createLeaf(), tag: nodeKindLeaf}
 			ref.addChild(keyS[depth], leafRef)
 			t.size++
+			if t.sums != nil {
+				t.sums.insert(path, val)
+			}
 			return
 		}
 
 		nl := (*unsignedLeafNode[V])(ref.pointer)
+		t.opts.checker.check(ref.pointer, nl.getKey())
 
 		if bytes.Equal(keyS, nl.getKey()) {
+			old := nl.value
 			nl.value = val
+			if t.sums != nil {
+				t.sums.replace(path, old, val)
+			}
 			return
 		}
 
@@ -567,6 +663,12 @@ func (t *unsignedSortedTree[K, V]) Insert(key K, val V) {
 			newNode.addChild(ref, keyS[splitPrefix], leafRef)
 		}
 		t.size++
+		if t.sums != nil {
+			t.sums.node(*ref)
+			if splitPrefix < len(keyS) {
+				t.sums.insert(path, val)
+			}
+		}
 		return
 	}
 }
@@ -603,35 +705,28 @@ func (t *unsignedSortedTree[K, V]) Prefix(p K) iter.Seq2[K, V] {
 
 }
 
-func (t *unsignedSortedTree[K, V]) Range(start, end K) iter.Seq2[K, V] {
+func (t *unsignedSortedTree[K, V]) PrefixBackward(p K) iter.Seq2[K, V] {
 
-	if start == end {
-		return func(yield func(K, V) bool) {
-			val, ok := t.Search(start)
-			if !ok {
-				return
-			}
+	panic("")
 
-			if !yield(start, val) {
-				return
-			}
-		}
-	}
+}
 
-	if start > end {
-		// IDEA: maybe do the iteration in reverse instead?
-		start, end = end, start
-	}
+func (t *unsignedSortedTree[K, V]) Range(start, end K) iter.Seq2[K, V] {
+	startKey := t.encodeKey(start)
+	endKey := t.encodeKey(end)
 
-	startKey, _ := t.bck.Transform(start)
-	endKey, _ := t.bck.Transform(end)
+	bounds := rangeBounds(startKey, endKey, false)
 
-	return rangeScan[K, V, *unsignedLeafNode[V]](t.root, startKey, endKey, startKey, endKey, t.restoreKey)
+	return rangeScan[K, V, *unsignedLeafNode[V]](t.root, bounds, t.restoreKey, &t.overflows)
+}
 
+func (t *unsignedSortedTree[K, V]) RangeWith(opts RangeOptions[K]) iter.Seq2[K, V] {
+	bounds := newScanBounds(opts, t.encodeKey)
+	return rangeScan[K, V, *unsignedLeafNode[V]](t.root, bounds, t.restoreKey, &t.overflows)
 }
 
 func (t *unsignedSortedTree[K, V]) Search(key K) (V, bool) {
-	_, keyS := t.bck.Transform(key)
+	keyS := t.encodeKey(key)
 
 	var notFound V
 
@@ -696,6 +791,7 @@ func (t *unsignedSortedTree[K, V]) Search(key K) (V, bool) {
 		}
 
 		leaf := (*unsignedLeafNode[V])(n.pointer)
+		t.opts.checker.check(n.pointer, leaf.getKey())
 
 		if bytes.Equal(leaf.getKey(), keyS) {
 			return leaf.value, true
@@ -712,9 +808,34 @@ func (t *unsignedSortedTree[K, V]) TopK(k uint) iter.Seq2[K, V] {
 
 func (t *unsignedSortedTree[K, V]) Size() int { return t.size }
 
+func (t *unsignedSortedTree[K, V]) rootRef() nodeRef { return t.root }
+
+func (t *unsignedSortedTree[K, V]) setSummaries(s summaries[V]) {
+	if t.sums != nil {
+		panic("art: the tree already has an AggregateTree")
+	}
+	t.sums = s
+}
+
+func (t *unsignedSortedTree[K, V]) leafKey(ptr unsafe.Pointer) []byte {
+	return (*unsignedLeafNode[V])(ptr).getTransformKey()
+}
+
+func (t *unsignedSortedTree[K, V]) leafValue(ptr unsafe.Pointer) V {
+	return (*unsignedLeafNode[V])(ptr).value
+}
+
+// Stats walks the tree and reports its shape and memory footprint.
+func (t *unsignedSortedTree[K, V]) Stats() Stats {
+	s := stats[V, *unsignedLeafNode[V]](t.root, unsafe.Sizeof(unsignedLeafNode[V]{}))
+	s.OverflowLookups = int(t.overflows.Load())
+
+	return s
+}
+
 type signedLeafNode[V any] struct {
+	value V // first, so that a struct{} value takes no space
 	key   *byte
-	value V
 	len   uint32
 }
 
@@ -723,15 +844,18 @@ func (n *signedLeafNode[V]) getTransformKey() []byte { return unsafe.Slice(n.key
 
 type signedSortedTree[K ints, V any] struct {
 	root nodeRef
-	bck  SignedBinaryKey[K]
+	signedCodec[K]
 	size int
+
+	overflows atomic.Int64
+	sums      summaries[V]
 }
 
 func (t *signedSortedTree[K, V]) restoreKey(ptr unsafe.Pointer) (K, V) {
 	l := (*signedLeafNode[V])(ptr)
 	keyS := l.getKey()
-
-	return t.bck.Restore(keyS), l.value
+	t.opts.checker.check(ptr, keyS)
+	return t.decodeKey(keyS), l.value
 }
 
 func (t *signedSortedTree[K, V]) All() iter.Seq2[K, V] {
@@ -751,17 +875,21 @@ func (t *signedSortedTree[K, V]) Delete(key K) bool {
 		return false
 	}
 
-	_, keyS := t.bck.Transform(key)
+	keyS := t.encodeKey(key)
 
 	ref := &t.root
 	n := *ref
 	depth := 0
 
+	var path []pathNode // the inner nodes above the leaf, for the summaries
+
 	for n.pointer != nil {
 		if n.tag == nodeKindLeaf {
 			leaf := (*signedLeafNode[V])(n.pointer)
+			t.opts.checker.check(n.pointer, leaf.getKey())
 
 			if bytes.Equal(leaf.getKey(), keyS) {
+				t.opts.checker.forget(n.pointer)
 				*ref = nodeRef{}
 				t.size--
 				return true
@@ -784,13 +912,21 @@ func (t *signedSortedTree[K, V]) Delete(key K) bool {
 		if child == nil {
 			return false
 		}
+		if t.sums != nil {
+			path = append(path, pathNode{ref, *ref})
+		}
 
 		if child.tag == nodeKindLeaf {
 			leaf := (*signedLeafNode[V])(child.pointer)
+			t.opts.checker.check(child.pointer, leaf.getKey())
 
 			if bytes.Equal(leaf.getKey(), keyS) {
+				t.opts.checker.forget(child.pointer)
 				ref.deleteChild(keyS[depth])
 				t.size--
+				if t.sums != nil {
+					t.sums.remove(path, leaf.value)
+				}
 				return true
 			}
 
@@ -805,14 +941,18 @@ func (t *signedSortedTree[K, V]) Delete(key K) bool {
 }
 
 func (t *signedSortedTree[K, V]) Insert(key K, val V) {
-	_, keyS := t.bck.Transform(key)
+	keyS := t.encodeKey(key)
 
 	createLeaf := func() unsafe.Pointer {
-		return unsafe.Pointer(&signedLeafNode[V]{
-			key:   unsafe.SliceData(keyS),
+
+		leaf := unsafe.Pointer(&signedLeafNode[V]{
 			value: val,
+			key:   unsafe.SliceData(keyS),
 			len:   uint32(len(keyS)),
 		})
+
+		t.opts.checker.record(leaf, keyS)
+		return leaf
 	}
 
 	if t.root.pointer == nil {
@@ -825,11 +965,13 @@ func (t *signedSortedTree[K, V]) Insert(key K, val V) {
 	n := *ref
 	depth := 0
 
+	var path []pathNode // the inner nodes above the leaf, for the summaries
+
 	for ref.pointer != nil {
 		if ref.tag != nodeKindLeaf {
 			node := ref.node()
 			if node.prefixLen != 0 {
-				prefixDiff := prefixMismatch[V, *signedLeafNode[V]](n, keyS, depth)
+				prefixDiff := prefixMismatch[V, *signedLeafNode[V]](n, keyS, depth, &t.overflows)
 
 				if prefixDiff >= int(node.prefixLen) {
 					depth += int(node.prefixLen)
@@ -850,6 +992,7 @@ func (t *signedSortedTree[K, V]) Insert(key K, val V) {
 					copy(node.prefix[:], node.prefix[loLimit:])
 				} else {
 					node.prefixLen -= uint32(prefixDiff + 1)
+					t.overflows.Add(1) // loading the rest of the path
 					leafMin := (*signedLeafNode[V])(minimum[V](n))
 					leafKey := leafMin.getTransformKey()
 
@@ -859,10 +1002,18 @@ func (t *signedSortedTree[K, V]) Insert(key K, val V) {
 				}
 
 				if depth+prefixDiff >= len(keyS) {
+					if t.sums != nil {
+						t.sums.node(*ref)
+					}
 					return
 				}
 				leafRef := nodeRef{pointer: createLeaf(), tag: nodeKindLeaf}
 				newNode.addChild(ref, keyS[depth+prefixDiff], leafRef)
+				t.size++
+				if t.sums != nil {
+					t.sums.node(*ref)
+					t.sums.insert(path, val)
+				}
 				return
 			}
 
@@ -870,6 +1021,9 @@ func (t *signedSortedTree[K, V]) Insert(key K, val V) {
 			if depth >= len(keyS) {
 				return
 			}
+			if t.sums != nil {
+				path = append(path, pathNode{ref, *ref})
+			}
 
 			child := ref.findChild(keyS[depth])
 			if child != nil {
@@ -882,13 +1036,21 @@ func (t *signedSortedTree[K, V]) Insert(key K, val V) {
 			leafRef := nodeRef{pointer: createLeaf(), tag: nodeKindLeaf}
 			ref.addChild(keyS[depth], leafRef)
 			t.size++
+			if t.sums != nil {
+				t.sums.insert(path, val)
+			}
 			return
 		}
 
 		nl := (*signedLeafNode[V])(ref.pointer)
+		t.opts.checker.check(ref.pointer, nl.getKey())
 
 		if bytes.Equal(keyS, nl.getKey()) {
+			old := nl.value
 			nl.value = val
+			if t.sums != nil {
+				t.sums.replace(path, old, val)
+			}
 			return
 		}
 
@@ -912,6 +1074,12 @@ func (t *signedSortedTree[K, V]) Insert(key K, val V) {
 			newNode.addChild(ref, keyS[splitPrefix], leafRef)
 		}
 		t.size++
+		if t.sums != nil {
+			t.sums.node(*ref)
+			if splitPrefix < len(keyS) {
+				t.sums.insert(path, val)
+			}
+		}
 		return
 	}
 }
@@ -948,35 +1116,28 @@ func (t *signedSortedTree[K, V]) Prefix(p K) iter.Seq2[K, V] {
 
 }
 
-func (t *signedSortedTree[K, V]) Range(start, end K) iter.Seq2[K, V] {
+func (t *signedSortedTree[K, V]) PrefixBackward(p K) iter.Seq2[K, V] {
 
-	if start == end {
-		return func(yield func(K, V) bool) {
-			val, ok := t.Search(start)
-			if !ok {
-				return
-			}
+	panic("")
 
-			if !yield(start, val) {
-				return
-			}
-		}
-	}
+}
 
-	if start > end {
-		// IDEA: maybe do the iteration in reverse instead?
-		start, end = end, start
-	}
+func (t *signedSortedTree[K, V]) Range(start, end K) iter.Seq2[K, V] {
+	startKey := t.encodeKey(start)
+	endKey := t.encodeKey(end)
 
-	startKey, _ := t.bck.Transform(start)
-	endKey, _ := t.bck.Transform(end)
+	bounds := rangeBounds(startKey, endKey, false)
 
-	return rangeScan[K, V, *unsignedLeafNode[V]](t.root, startKey, endKey, startKey, endKey, t.restoreKey)
+	return rangeScan[K, V, *signedLeafNode[V]](t.root, bounds, t.restoreKey, &t.overflows)
+}
 
+func (t *signedSortedTree[K, V]) RangeWith(opts RangeOptions[K]) iter.Seq2[K, V] {
+	bounds := newScanBounds(opts, t.encodeKey)
+	return rangeScan[K, V, *signedLeafNode[V]](t.root, bounds, t.restoreKey, &t.overflows)
 }
 
 func (t *signedSortedTree[K, V]) Search(key K) (V, bool) {
-	_, keyS := t.bck.Transform(key)
+	keyS := t.encodeKey(key)
 
 	var notFound V
 
@@ -1041,6 +1202,7 @@ func (t *signedSortedTree[K, V]) Search(key K) (V, bool) {
 		}
 
 		leaf := (*signedLeafNode[V])(n.pointer)
+		t.opts.checker.check(n.pointer, leaf.getKey())
 
 		if bytes.Equal(leaf.getKey(), keyS) {
 			return leaf.value, true
@@ -1057,9 +1219,34 @@ func (t *signedSortedTree[K, V]) TopK(k uint) iter.Seq2[K, V] {
 
 func (t *signedSortedTree[K, V]) Size() int { return t.size }
 
+func (t *signedSortedTree[K, V]) rootRef() nodeRef { return t.root }
+
+func (t *signedSortedTree[K, V]) setSummaries(s summaries[V]) {
+	if t.sums != nil {
+		panic("art: the tree already has an AggregateTree")
+	}
+	t.sums = s
+}
+
+func (t *signedSortedTree[K, V]) leafKey(ptr unsafe.Pointer) []byte {
+	return (*signedLeafNode[V])(ptr).getTransformKey()
+}
+
+func (t *signedSortedTree[K, V]) leafValue(ptr unsafe.Pointer) V {
+	return (*signedLeafNode[V])(ptr).value
+}
+
+// Stats walks the tree and reports its shape and memory footprint.
+func (t *signedSortedTree[K, V]) Stats() Stats {
+	s := stats[V, *signedLeafNode[V]](t.root, unsafe.Sizeof(signedLeafNode[V]{}))
+	s.OverflowLookups = int(t.overflows.Load())
+
+	return s
+}
+
 type floatLeafNode[V any] struct {
+	value V // first, so that a struct{} value takes no space
 	key   *byte
-	value V
 	len   uint32
 }
 
@@ -1068,15 +1255,18 @@ func (n *floatLeafNode[V]) getTransformKey() []byte { return unsafe.Slice(n.key,
 
 type floatSortedTree[K floats, V any] struct {
 	root nodeRef
-	bck  FloatBinaryKey[K]
+	floatCodec[K]
 	size int
+
+	overflows atomic.Int64
+	sums      summaries[V]
 }
 
 func (t *floatSortedTree[K, V]) restoreKey(ptr unsafe.Pointer) (K, V) {
 	l := (*floatLeafNode[V])(ptr)
 	keyS := l.getKey()
-
-	return t.bck.Restore(keyS), l.value
+	t.opts.checker.check(ptr, keyS)
+	return t.decodeKey(keyS), l.value
 }
 
 func (t *floatSortedTree[K, V]) All() iter.Seq2[K, V] {
@@ -1096,17 +1286,21 @@ func (t *floatSortedTree[K, V]) Delete(key K) bool {
 		return false
 	}
 
-	_, keyS := t.bck.Transform(key)
+	keyS := t.encodeKey(key)
 
 	ref := &t.root
 	n := *ref
 	depth := 0
 
+	var path []pathNode // the inner nodes above the leaf, for the summaries
+
 	for n.pointer != nil {
 		if n.tag == nodeKindLeaf {
 			leaf := (*floatLeafNode[V])(n.pointer)
+			t.opts.checker.check(n.pointer, leaf.getKey())
 
 			if bytes.Equal(leaf.getKey(), keyS) {
+				t.opts.checker.forget(n.pointer)
 				*ref = nodeRef{}
 				t.size--
 				return true
@@ -1129,13 +1323,21 @@ func (t *floatSortedTree[K, V]) Delete(key K) bool {
 		if child == nil {
 			return false
 		}
+		if t.sums != nil {
+			path = append(path, pathNode{ref, *ref})
+		}
 
 		if child.tag == nodeKindLeaf {
 			leaf := (*floatLeafNode[V])(child.pointer)
+			t.opts.checker.check(child.pointer, leaf.getKey())
 
 			if bytes.Equal(leaf.getKey(), keyS) {
+				t.opts.checker.forget(child.pointer)
 				ref.deleteChild(keyS[depth])
 				t.size--
+				if t.sums != nil {
+					t.sums.remove(path, leaf.value)
+				}
 				return true
 			}
 
@@ -1150,14 +1352,18 @@ func (t *floatSortedTree[K, V]) Delete(key K) bool {
 }
 
 func (t *floatSortedTree[K, V]) Insert(key K, val V) {
-	_, keyS := t.bck.Transform(key)
+	keyS := t.encodeKey(key)
 
 	createLeaf := func() unsafe.Pointer {
-		return unsafe.Pointer(&floatLeafNode[V]{
-			key:   unsafe.SliceData(keyS),
+
+		leaf := unsafe.Pointer(&floatLeafNode[V]{
 			value: val,
+			key:   unsafe.SliceData(keyS),
 			len:   uint32(len(keyS)),
 		})
+
+		t.opts.checker.record(leaf, keyS)
+		return leaf
 	}
 
 	if t.root.pointer == nil {
@@ -1170,11 +1376,13 @@ func (t *floatSortedTree[K, V]) Insert(key K, val V) {
 	n := *ref
 	depth := 0
 
+	var path []pathNode // the inner nodes above the leaf, for the summaries
+
 	for ref.pointer != nil {
 		if ref.tag != nodeKindLeaf {
 			node := ref.node()
 			if node.prefixLen != 0 {
-				prefixDiff := prefixMismatch[V, *floatLeafNode[V]](n, keyS, depth)
+				prefixDiff := prefixMismatch[V, *floatLeafNode[V]](n, keyS, depth, &t.overflows)
 
 				if prefixDiff >= int(node.prefixLen) {
 					depth += int(node.prefixLen)
@@ -1195,6 +1403,7 @@ func (t *floatSortedTree[K, V]) Insert(key K, val V) {
 					copy(node.prefix[:], node.prefix[loLimit:])
 				} else {
 					node.prefixLen -= uint32(prefixDiff + 1)
+					t.overflows.Add(1) // loading the rest of the path
 					leafMin := (*floatLeafNode[V])(minimum[V](n))
 					leafKey := leafMin.getTransformKey()
 
@@ -1204,10 +1413,18 @@ func (t *floatSortedTree[K, V]) Insert(key K, val V) {
 				}
 
 				if depth+prefixDiff >= len(keyS) {
+					if t.sums != nil {
+						t.sums.node(*ref)
+					}
 					return
 				}
 				leafRef := nodeRef{pointer: createLeaf(), tag: nodeKindLeaf}
 				newNode.addChild(ref, keyS[depth+prefixDiff], leafRef)
+				t.size++
+				if t.sums != nil {
+					t.sums.node(*ref)
+					t.sums.insert(path, val)
+				}
 				return
 			}
 
@@ -1215,6 +1432,9 @@ func (t *floatSortedTree[K, V]) Insert(key K, val V) {
 			if depth >= len(keyS) {
 				return
 			}
+			if t.sums != nil {
+				path = append(path, pathNode{ref, *ref})
+			}
 
 			child := ref.findChild(keyS[depth])
 			if child != nil {
@@ -1227,13 +1447,21 @@ func (t *floatSortedTree[K, V]) Insert(key K, val V) {
 			leafRef := nodeRef{pointer: createLeaf(), tag: nodeKindLeaf}
 			ref.addChild(keyS[depth], leafRef)
 			t.size++
+			if t.sums != nil {
+				t.sums.insert(path, val)
+			}
 			return
 		}
 
 		nl := (*floatLeafNode[V])(ref.pointer)
+		t.opts.checker.check(ref.pointer, nl.getKey())
 
 		if bytes.Equal(keyS, nl.getKey()) {
+			old := nl.value
 			nl.value = val
+			if t.sums != nil {
+				t.sums.replace(path, old, val)
+			}
 			return
 		}
 
@@ -1257,6 +1485,12 @@ func (t *floatSortedTree[K, V]) Insert(key K, val V) {
 			newNode.addChild(ref, keyS[splitPrefix], leafRef)
 		}
 		t.size++
+		if t.sums != nil {
+			t.sums.node(*ref)
+			if splitPrefix < len(keyS) {
+				t.sums.insert(path, val)
+			}
+		}
 		return
 	}
 }
@@ -1293,35 +1527,28 @@ func (t *floatSortedTree[K, V]) Prefix(p K) iter.Seq2[K, V] {
 
 }
 
-func (t *floatSortedTree[K, V]) Range(start, end K) iter.Seq2[K, V] {
+func (t *floatSortedTree[K, V]) PrefixBackward(p K) iter.Seq2[K, V] {
 
-	if start == end {
-		return func(yield func(K, V) bool) {
-			val, ok := t.Search(start)
-			if !ok {
-				return
-			}
+	panic("")
 
-			if !yield(start, val) {
-				return
-			}
-		}
-	}
+}
 
-	if start > end {
-		// IDEA: maybe do the iteration in reverse instead?
-		start, end = end, start
-	}
+func (t *floatSortedTree[K, V]) Range(start, end K) iter.Seq2[K, V] {
+	startKey := t.encodeKey(start)
+	endKey := t.encodeKey(end)
 
-	startKey, _ := t.bck.Transform(start)
-	endKey, _ := t.bck.Transform(end)
+	bounds := rangeBounds(startKey, endKey, false)
 
-	return rangeScan[K, V, *unsignedLeafNode[V]](t.root, startKey, endKey, startKey, endKey, t.restoreKey)
+	return rangeScan[K, V, *floatLeafNode[V]](t.root, bounds, t.restoreKey, &t.overflows)
+}
 
+func (t *floatSortedTree[K, V]) RangeWith(opts RangeOptions[K]) iter.Seq2[K, V] {
+	bounds := newScanBounds(opts, t.encodeKey)
+	return rangeScan[K, V, *floatLeafNode[V]](t.root, bounds, t.restoreKey, &t.overflows)
 }
 
 func (t *floatSortedTree[K, V]) Search(key K) (V, bool) {
-	_, keyS := t.bck.Transform(key)
+	keyS := t.encodeKey(key)
 
 	var notFound V
 
@@ -1386,6 +1613,7 @@ func (t *floatSortedTree[K, V]) Search(key K) (V, bool) {
 		}
 
 		leaf := (*floatLeafNode[V])(n.pointer)
+		t.opts.checker.check(n.pointer, leaf.getKey())
 
 		if bytes.Equal(leaf.getKey(), keyS) {
 			return leaf.value, true
@@ -1402,9 +1630,34 @@ func (t *floatSortedTree[K, V]) TopK(k uint) iter.Seq2[K, V] {
 
 func (t *floatSortedTree[K, V]) Size() int { return t.size }
 
+func (t *floatSortedTree[K, V]) rootRef() nodeRef { return t.root }
+
+func (t *floatSortedTree[K, V]) setSummaries(s summaries[V]) {
+	if t.sums != nil {
+		panic("art: the tree already has an AggregateTree")
+	}
+	t.sums = s
+}
+
+func (t *floatSortedTree[K, V]) leafKey(ptr unsafe.Pointer) []byte {
+	return (*floatLeafNode[V])(ptr).getTransformKey()
+}
+
+func (t *floatSortedTree[K, V]) leafValue(ptr unsafe.Pointer) V {
+	return (*floatLeafNode[V])(ptr).value
+}
+
+// Stats walks the tree and reports its shape and memory footprint.
+func (t *floatSortedTree[K, V]) Stats() Stats {
+	s := stats[V, *floatLeafNode[V]](t.root, unsafe.Sizeof(floatLeafNode[V]{}))
+	s.OverflowLookups = int(t.overflows.Load())
+
+	return s
+}
+
 type compoundLeafNode[V any] struct {
+	value V // first, so that a struct{} value takes no space
 	key   *byte
-	value V
 	len   uint32
 }
 
@@ -1413,15 +1666,18 @@ func (n *compoundLeafNode[V]) getTransformKey() []byte { return unsafe.Slice(n.k
 
 type compoundSortedTree[K any, V any] struct {
 	root nodeRef
-	bck  BinaryComparableKey[K]
+	compoundCodec[K]
 	size int
+
+	overflows atomic.Int64
+	sums      summaries[V]
 }
 
 func (t *compoundSortedTree[K, V]) restoreKey(ptr unsafe.Pointer) (K, V) {
 	l := (*compoundLeafNode[V])(ptr)
 	keyS := l.getKey()
-
-	return t.bck.Restore(keyS), l.value
+	t.opts.checker.check(ptr, keyS)
+	return t.decodeKey(keyS), l.value
 }
 
 func (t *compoundSortedTree[K, V]) All() iter.Seq2[K, V] {
@@ -1441,17 +1697,21 @@ func (t *compoundSortedTree[K, V]) Delete(key K) bool {
 		return false
 	}
 
-	_, keyS := t.bck.Transform(key)
+	keyS := t.encodeKey(key)
 
 	ref := &t.root
 	n := *ref
 	depth := 0
 
+	var path []pathNode // the inner nodes above the leaf, for the summaries
+
 	for n.pointer != nil {
 		if n.tag == nodeKindLeaf {
 			leaf := (*compoundLeafNode[V])(n.pointer)
+			t.opts.checker.check(n.pointer, leaf.getKey())
 
 			if bytes.Equal(leaf.getKey(), keyS) {
+				t.opts.checker.forget(n.pointer)
 				*ref = nodeRef{}
 				t.size--
 				return true
@@ -1474,13 +1734,21 @@ func (t *compoundSortedTree[K, V]) Delete(key K) bool {
 		if child == nil {
 			return false
 		}
+		if t.sums != nil {
+			path = append(path, pathNode{ref, *ref})
+		}
 
 		if child.tag == nodeKindLeaf {
 			leaf := (*compoundLeafNode[V])(child.pointer)
+			t.opts.checker.check(child.pointer, leaf.getKey())
 
 			if bytes.Equal(leaf.getKey(), keyS) {
+				t.opts.checker.forget(child.pointer)
 				ref.deleteChild(keyS[depth])
 				t.size--
+				if t.sums != nil {
+					t.sums.remove(path, leaf.value)
+				}
 				return true
 			}
 
@@ -1495,14 +1763,18 @@ func (t *compoundSortedTree[K, V]) Delete(key K) bool {
 }
 
 func (t *compoundSortedTree[K, V]) Insert(key K, val V) {
-	_, keyS := t.bck.Transform(key)
+	keyS := t.encodeKey(key)
 
 	createLeaf := func() unsafe.Pointer {
-		return unsafe.Pointer(&compoundLeafNode[V]{
-			key:   unsafe.SliceData(keyS),
+
+		leaf := unsafe.Pointer(&compoundLeafNode[V]{
 			value: val,
+			key:   unsafe.SliceData(keyS),
 			len:   uint32(len(keyS)),
 		})
+
+		t.opts.checker.record(leaf, keyS)
+		return leaf
 	}
 
 	if t.root.pointer == nil {
@@ -1515,11 +1787,13 @@ func (t *compoundSortedTree[K, V]) Insert(key K, val V) {
 	n := *ref
 	depth := 0
 
+	var path []pathNode // the inner nodes above the leaf, for the summaries
+
 	for ref.pointer != nil {
 		if ref.tag != nodeKindLeaf {
 			node := ref.node()
 			if node.prefixLen != 0 {
-				prefixDiff := prefixMismatch[V, *compoundLeafNode[V]](n, keyS, depth)
+				prefixDiff := prefixMismatch[V, *compoundLeafNode[V]](n, keyS, depth, &t.overflows)
 
 				if prefixDiff >= int(node.prefixLen) {
 					depth += int(node.prefixLen)
@@ -1540,6 +1814,7 @@ func (t *compoundSortedTree[K, V]) Insert(key K, val V) {
 					copy(node.prefix[:], node.prefix[loLimit:])
 				} else {
 					node.prefixLen -= uint32(prefixDiff + 1)
+					t.overflows.Add(1) // loading the rest of the path
 					leafMin := (*compoundLeafNode[V])(minimum[V](n))
 					leafKey := leafMin.getTransformKey()
 
@@ -1549,10 +1824,18 @@ func (t *compoundSortedTree[K, V]) Insert(key K, val V) {
 				}
 
 				if depth+prefixDiff >= len(keyS) {
+					if t.sums != nil {
+						t.sums.node(*ref)
+					}
 					return
 				}
 				leafRef := nodeRef{pointer: createLeaf(), tag: nodeKindLeaf}
 				newNode.addChild(ref, keyS[depth+prefixDiff], leafRef)
+				t.size++
+				if t.sums != nil {
+					t.sums.node(*ref)
+					t.sums.insert(path, val)
+				}
 				return
 			}
 
@@ -1560,6 +1843,9 @@ func (t *compoundSortedTree[K, V]) Insert(key K, val V) {
 			if depth >= len(keyS) {
 				return
 			}
+			if t.sums != nil {
+				path = append(path, pathNode{ref, *ref})
+			}
 
 			child := ref.findChild(keyS[depth])
 			if child != nil {
@@ -1572,13 +1858,21 @@ func (t *compoundSortedTree[K, V]) Insert(key K, val V) {
 			leafRef := nodeRef{pointer: createLeaf(), tag: nodeKindLeaf}
 			ref.addChild(keyS[depth], leafRef)
 			t.size++
+			if t.sums != nil {
+				t.sums.insert(path, val)
+			}
 			return
 		}
 
 		nl := (*compoundLeafNode[V])(ref.pointer)
+		t.opts.checker.check(ref.pointer, nl.getKey())
 
 		if bytes.Equal(keyS, nl.getKey()) {
+			old := nl.value
 			nl.value = val
+			if t.sums != nil {
+				t.sums.replace(path, old, val)
+			}
 			return
 		}
 
@@ -1602,6 +1896,12 @@ func (t *compoundSortedTree[K, V]) Insert(key K, val V) {
 			newNode.addChild(ref, keyS[splitPrefix], leafRef)
 		}
 		t.size++
+		if t.sums != nil {
+			t.sums.node(*ref)
+			if splitPrefix < len(keyS) {
+				t.sums.insert(path, val)
+			}
+		}
 		return
 	}
 }
@@ -1634,31 +1934,34 @@ func (t *compoundSortedTree[K, V]) Minimum() (K, V, bool) {
 
 func (t *compoundSortedTree[K, V]) Prefix(p K) iter.Seq2[K, V] {
 
-	panic("")
+	root := prefixRoot[V, *compoundLeafNode[V]](t.root, t.encodePrefix(p), &t.overflows)
+	return all(root, t.restoreKey)
 
 }
 
-func (t *compoundSortedTree[K, V]) Range(start, end K) iter.Seq2[K, V] {
+func (t *compoundSortedTree[K, V]) PrefixBackward(p K) iter.Seq2[K, V] {
 
-	startKey, _ := t.bck.Transform(start)
-	endKey, _ := t.bck.Transform(end)
+	root := prefixRoot[V, *compoundLeafNode[V]](t.root, t.encodePrefix(p), &t.overflows)
+	return backward(root, t.restoreKey)
 
-	if len(endKey) == 0 {
-		end, _ = t.restoreKey(maximum[V](t.root))
-		endKey, _ = t.bck.Transform(end) // NOT GREAT!
-	}
+}
 
-	if bytes.Compare(startKey, endKey) > 0 { // start > end
-		// IDEA: maybe do the iteration in reverse instead?
-		startKey, endKey = endKey, startKey
-	}
+func (t *compoundSortedTree[K, V]) Range(start, end K) iter.Seq2[K, V] {
+	startKey := t.encodeKey(start)
+	endKey := t.encodeKey(end)
 
-	return rangeScan[K, V, *compoundLeafNode[V]](t.root, startKey, endKey, startKey, endKey, t.restoreKey)
+	bounds := rangeBounds(startKey, endKey, len(endKey) == 0)
+
+	return rangeScan[K, V, *compoundLeafNode[V]](t.root, bounds, t.restoreKey, &t.overflows)
+}
 
+func (t *compoundSortedTree[K, V]) RangeWith(opts RangeOptions[K]) iter.Seq2[K, V] {
+	bounds := newScanBounds(opts, t.encodeKey)
+	return rangeScan[K, V, *compoundLeafNode[V]](t.root, bounds, t.restoreKey, &t.overflows)
 }
 
 func (t *compoundSortedTree[K, V]) Search(key K) (V, bool) {
-	_, keyS := t.bck.Transform(key)
+	keyS := t.encodeKey(key)
 
 	var notFound V
 
@@ -1723,6 +2026,7 @@ func (t *compoundSortedTree[K, V]) Search(key K) (V, bool) {
 		}
 
 		leaf := (*compoundLeafNode[V])(n.pointer)
+		t.opts.checker.check(n.pointer, leaf.getKey())
 
 		if bytes.Equal(leaf.getKey(), keyS) {
 			return leaf.value, true
@@ -1738,3 +2042,438 @@ func (t *compoundSortedTree[K, V]) TopK(k uint) iter.Seq2[K, V] {
 }
 
 func (t *compoundSortedTree[K, V]) Size() int { return t.size }
+
+func (t *compoundSortedTree[K, V]) rootRef() nodeRef { return t.root }
+
+func (t *compoundSortedTree[K, V]) setSummaries(s summaries[V]) {
+	if t.sums != nil {
+		panic("art: the tree already has an AggregateTree")
+	}
+	t.sums = s
+}
+
+func (t *compoundSortedTree[K, V]) leafKey(ptr unsafe.Pointer) []byte {
+	return (*compoundLeafNode[V])(ptr).getTransformKey()
+}
+
+func (t *compoundSortedTree[K, V]) leafValue(ptr unsafe.Pointer) V {
+	return (*compoundLeafNode[V])(ptr).value
+}
+
+// Stats walks the tree and reports its shape and memory footprint.
+func (t *compoundSortedTree[K, V]) Stats() Stats {
+	s := stats[V, *compoundLeafNode[V]](t.root, unsafe.Sizeof(compoundLeafNode[V]{}))
+	s.OverflowLookups = int(t.overflows.Load())
+
+	return s
+}
+
+type uuidLeafNode[V any] struct {
+	value V // first, so that a struct{} value takes no space
+	key   [16]byte
+}
+
+func (n *uuidLeafNode[V]) getKey() []byte          { return n.key[:] }
+func (n *uuidLeafNode[V]) getTransformKey() []byte { return n.key[:] }
+
+type uuidSortedTree[K ~[16]byte, V any] struct {
+	root nodeRef
+	uuidCodec[K]
+	size int
+
+	overflows atomic.Int64
+	sums      summaries[V]
+}
+
+func (t *uuidSortedTree[K, V]) restoreKey(ptr unsafe.Pointer) (K, V) {
+	l := (*uuidLeafNode[V])(ptr)
+	keyS := l.getKey()
+	t.opts.checker.check(ptr, keyS)
+	return t.decodeKey(keyS), l.value
+}
+
+func (t *uuidSortedTree[K, V]) All() iter.Seq2[K, V] {
+	return all(t.root, t.restoreKey)
+}
+
+func (t *uuidSortedTree[K, V]) Backward() iter.Seq2[K, V] {
+	return backward(t.root, t.restoreKey)
+}
+
+func (t *uuidSortedTree[K, V]) BottomK(k uint) iter.Seq2[K, V] {
+	return bottomK(t, k)
+}
+
+func (t *uuidSortedTree[K, V]) Delete(key K) bool {
+	if t.root.pointer == nil {
+		return false
+	}
+
+	keyS := t.encodeKey(key)
+
+	ref := &t.root
+	n := *ref
+	depth := 0
+
+	var path []pathNode // the inner nodes above the leaf, for the summaries
+
+	for n.pointer != nil {
+		if n.tag == nodeKindLeaf {
+			leaf := (*uuidLeafNode[V])(n.pointer)
+			t.opts.checker.check(n.pointer, leaf.getKey())
+
+			if bytes.Equal(leaf.getKey(), keyS) {
+				t.opts.checker.forget(n.pointer)
+				*ref = nodeRef{}
+				t.size--
+				return true
+			}
+
+			return false
+		}
+
+		node := n.node()
+		if node.prefixLen != 0 {
+			prefixLen := node.checkPrefix(keyS, depth)
+			if prefixLen != int(min(maxPrefixLen, node.prefixLen)) {
+				return false
+			}
+			depth += int(node.prefixLen)
+		}
+
+		child := n.findChild(keyS[depth])
+
+		if child == nil {
+			return false
+		}
+		if t.sums != nil {
+			path = append(path, pathNode{ref, *ref})
+		}
+
+		if child.tag == nodeKindLeaf {
+			leaf := (*uuidLeafNode[V])(child.pointer)
+			t.opts.checker.check(child.pointer, leaf.getKey())
+
+			if bytes.Equal(leaf.getKey(), keyS) {
+				t.opts.checker.forget(child.pointer)
+				ref.deleteChild(keyS[depth])
+				t.size--
+				if t.sums != nil {
+					t.sums.remove(path, leaf.value)
+				}
+				return true
+			}
+
+			return false
+		}
+
+		n = *child
+		ref = child
+		depth++
+	}
+	return false
+}
+
+func (t *uuidSortedTree[K, V]) Insert(key K, val V) {
+	keyS := t.encodeKey(key)
+
+	createLeaf := func() unsafe.Pointer {
+
+		l := &uuidLeafNode[V]{value: val}
+		copy(l.key[:], keyS)
+		leaf := unsafe.Pointer(l)
+
+		t.opts.checker.record(leaf, keyS)
+		return leaf
+	}
+
+	if t.root.pointer == nil {
+		t.root = nodeRef{pointer: createLeaf(), tag: nodeKindLeaf}
+		t.size++
+		return
+	}
+
+	ref := &t.root
+	n := *ref
+	depth := 0
+
+	var path []pathNode // the inner nodes above the leaf, for the summaries
+
+	for ref.pointer != nil {
+		if ref.tag != nodeKindLeaf {
+			node := ref.node()
+			if node.prefixLen != 0 {
+				prefixDiff := prefixMismatch[V, *uuidLeafNode[V]](n, keyS, depth, &t.overflows)
+
+				if prefixDiff >= int(node.prefixLen) {
+					depth += int(node.prefixLen)
+					goto CONTINUE_SEARCH
+				}
+
+				newNode := nodePools[nodeKind4].Get().(*node4)
+
+				*ref = nodeRef{pointer: unsafe.Pointer(newNode), tag: nodeKind4}
+
+				newNode.prefixLen = uint32(prefixDiff)
+				newNode.prefix = node.prefix
+
+				if node.prefixLen <= maxPrefixLen {
+					newNode.addChild(ref, node.prefix[prefixDiff], n)
+					loLimit := prefixDiff + 1
+					node.prefixLen -= uint32(loLimit)
+					copy(node.prefix[:], node.prefix[loLimit:])
+				} else {
+					node.prefixLen -= uint32(prefixDiff + 1)
+					t.overflows.Add(1) // loading the rest of the path
+					leafMin := (*uuidLeafNode[V])(minimum[V](n))
+					leafKey := leafMin.getTransformKey()
+
+					newNode.addChild(ref, leafKey[depth+prefixDiff], n)
+					loLimit := depth + prefixDiff + 1
+					copy(node.prefix[:], leafKey[loLimit:])
+				}
+
+				if depth+prefixDiff >= len(keyS) {
+					if t.sums != nil {
+						t.sums.node(*ref)
+					}
+					return
+				}
+				leafRef := nodeRef{pointer: createLeaf(), tag: nodeKindLeaf}
+				newNode.addChild(ref, keyS[depth+prefixDiff], leafRef)
+				t.size++
+				if t.sums != nil {
+					t.sums.node(*ref)
+					t.sums.insert(path, val)
+				}
+				return
+			}
+
+		CONTINUE_SEARCH:
+			if depth >= len(keyS) {
+				return
+			}
+			if t.sums != nil {
+				path = append(path, pathNode{ref, *ref})
+			}
+
+			child := ref.findChild(keyS[depth])
+			if child != nil {
+				n = *child
+				ref = child
+				depth++
+				continue
+			}
+
+			leafRef := nodeRef{pointer: createLeaf(), tag: nodeKindLeaf}
+			ref.addChild(keyS[depth], leafRef)
+			t.size++
+			if t.sums != nil {
+				t.sums.insert(path, val)
+			}
+			return
+		}
+
+		nl := (*uuidLeafNode[V])(ref.pointer)
+		t.opts.checker.check(ref.pointer, nl.getKey())
+
+		if bytes.Equal(keyS, nl.getKey()) {
+			old := nl.value
+			nl.value = val
+			if t.sums != nil {
+				t.sums.replace(path, old, val)
+			}
+			return
+		}
+
+		leafKey := nl.getTransformKey()
+		newNode := nodePools[nodeKind4].Get().(*node4)
+
+		longestPrefix := longestCommonPrefix(leafKey, keyS, depth)
+		newNode.prefixLen = uint32(longestPrefix)
+
+		copy(newNode.prefix[:], keyS[depth:])
+
+		*ref = nodeRef{pointer: unsafe.Pointer(newNode), tag: nodeKind4}
+
+		splitPrefix := int(depth + longestPrefix)
+		if splitPrefix < len(leafKey) {
+			newNode.addChild(ref, leafKey[splitPrefix], n)
+		}
+
+		if splitPrefix < len(keyS) {
+			leafRef := nodeRef{pointer: createLeaf(), tag: nodeKindLeaf}
+			newNode.addChild(ref, keyS[splitPrefix], leafRef)
+		}
+		t.size++
+		if t.sums != nil {
+			t.sums.node(*ref)
+			if splitPrefix < len(keyS) {
+				t.sums.insert(path, val)
+			}
+		}
+		return
+	}
+}
+
+func (t *uuidSortedTree[K, V]) Maximum() (K, V, bool) {
+	if l := maximum[V](t.root); l != nil {
+		k, v := t.restoreKey(l)
+		return k, v, true
+	}
+
+	var (
+		notFoundKey   K
+		notFoundValue V
+	)
+	return notFoundKey, notFoundValue, false
+}
+
+func (t *uuidSortedTree[K, V]) Minimum() (K, V, bool) {
+	if l := minimum[V](t.root); l != nil {
+		k, v := t.restoreKey(l)
+		return k, v, true
+	}
+
+	var (
+		notFoundKey   K
+		notFoundValue V
+	)
+	return notFoundKey, notFoundValue, false
+}
+
+func (t *uuidSortedTree[K, V]) Prefix(p K) iter.Seq2[K, V] {
+
+	panic("")
+
+}
+
+func (t *uuidSortedTree[K, V]) PrefixBackward(p K) iter.Seq2[K, V] {
+
+	panic("")
+
+}
+
+func (t *uuidSortedTree[K, V]) Range(start, end K) iter.Seq2[K, V] {
+	startKey := t.encodeKey(start)
+	endKey := t.encodeKey(end)
+
+	bounds := rangeBounds(startKey, endKey, false)
+
+	return rangeScan[K, V, *uuidLeafNode[V]](t.root, bounds, t.restoreKey, &t.overflows)
+}
+
+func (t *uuidSortedTree[K, V]) RangeWith(opts RangeOptions[K]) iter.Seq2[K, V] {
+	bounds := newScanBounds(opts, t.encodeKey)
+	return rangeScan[K, V, *uuidLeafNode[V]](t.root, bounds, t.restoreKey, &t.overflows)
+}
+
+func (t *uuidSortedTree[K, V]) Search(key K) (V, bool) {
+	keyS := t.encodeKey(key)
+
+	var notFound V
+
+	n := t.root
+	depth := 0
+
+	for n.pointer != nil {
+		if n.tag != nodeKindLeaf {
+			node := n.node()
+			if node.prefixLen != 0 {
+				prefixLen := node.checkPrefix(keyS, depth)
+
+				if prefixLen != int(min(maxPrefixLen, node.prefixLen)) {
+					return notFound, false
+				}
+
+				depth += int(node.prefixLen)
+			}
+
+			b := keyS[depth]
+			switch n.tag {
+			case nodeKind4:
+				n4 := (*node4)(n.pointer)
+
+				if i := searchNode4(n4.keys, b); i != -1 && i < int(n4.childrenLen) {
+					n = n4.children[i]
+					depth++
+					continue
+				}
+
+			case nodeKind16:
+				n16 := (*node16)(n.pointer)
+
+				if idx := searchNode16(&n16.keys, n16.childrenLen, b); idx != -1 {
+					n = n16.children[idx]
+					depth++
+					continue
+				}
+
+			case nodeKind48:
+				n48 := (*node48)(n.pointer)
+
+				if i := n48.keys[b]; i != 0 {
+					n = n48.children[i-1]
+					depth++
+					continue
+				}
+
+			case nodeKind256:
+				n256 := (*node256)(n.pointer)
+
+				if n256.children[b].pointer != nil {
+					n = n256.children[b]
+					depth++
+					continue
+				}
+
+			default:
+				panic("shouldn't be possible!")
+			}
+			break
+		}
+
+		leaf := (*uuidLeafNode[V])(n.pointer)
+		t.opts.checker.check(n.pointer, leaf.getKey())
+
+		if bytes.Equal(leaf.getKey(), keyS) {
+			return leaf.value, true
+		}
+		return notFound, false
+	}
+
+	return notFound, false
+}
+
+func (t *uuidSortedTree[K, V]) TopK(k uint) iter.Seq2[K, V] {
+	return topK(t, k)
+}
+
+func (t *uuidSortedTree[K, V]) Size() int { return t.size }
+
+func (t *uuidSortedTree[K, V]) rootRef() nodeRef { return t.root }
+
+func (t *uuidSortedTree[K, V]) setSummaries(s summaries[V]) {
+	if t.sums != nil {
+		panic("art: the tree already has an AggregateTree")
+	}
+	t.sums = s
+}
+
+func (t *uuidSortedTree[K, V]) leafKey(ptr unsafe.Pointer) []byte {
+	return (*uuidLeafNode[V])(ptr).getTransformKey()
+}
+
+func (t *uuidSortedTree[K, V]) leafValue(ptr unsafe.Pointer) V {
+	return (*uuidLeafNode[V])(ptr).value
+}
+
+// Stats walks the tree and reports its shape and memory footprint.
+func (t *uuidSortedTree[K, V]) Stats() Stats {
+	s := stats[V, *uuidLeafNode[V]](t.root, unsafe.Sizeof(uuidLeafNode[V]{}))
+	s.OverflowLookups = int(t.overflows.Load())
+
+	s.KeyBytes = 0 // the keys are stored in the leaves
+
+	return s
+}
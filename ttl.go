@@ -0,0 +1,326 @@
+package art
+
+import (
+	"encoding/binary"
+	"iter"
+	"slices"
+	"sync"
+	"time"
+)
+
+// TTLTree is a Tree whose entries can expire. The expired entries are never
+// returned: they are removed when Search or Delete finds them, skipped by the
+// iterations and removed in batches by Sweep and by the sweeper.
+type TTLTree[K nodeKey, V any] interface {
+	Tree[K, V]
+
+	// InsertWithTTL inserts an entry expiring after ttl. Insert inserts an
+	// entry that never expires.
+	InsertWithTTL(key K, val V, ttl time.Duration)
+
+	// TTL returns the time left before the entry of key expires, and false
+	// when there is no such entry or when it never expires.
+	TTL(key K) (time.Duration, bool)
+
+	// Sweep removes up to max expired entries, all of them when max isn't
+	// positive, and returns the number of entries removed.
+	Sweep(max int) int
+
+	// StartSweeper starts a goroutine calling Sweep(batch) every interval
+	// while holding mu, which must be the lock guarding the other calls. The
+	// returned function stops the sweeper and returns once it stopped.
+	StartSweeper(interval time.Duration, batch int, mu sync.Locker) (stop func())
+
+	// OnExpire sets the function called with each expired entry removed by
+	// the tree.
+	OnExpire(func(K, V))
+}
+
+// insertSweepBatch is the number of expired entries removed by each insert,
+// so that a tree under steady writes doesn't accumulate them without Sweep.
+const insertSweepBatch = 2
+
+// NewTTLTree returns a TTLTree storing its entries in the given tree, e.g.
+// NewAlphaSortedTree[string, TTLEntry[int]](). The deadlines are also indexed
+// by a compound tree so that Sweep only visits the expired entries. The
+// entries inserted in the inner tree directly never expire.
+//
+// Like the other trees, it isn't safe for concurrent use, which is why
+// StartSweeper takes the lock guarding the tree.
+func NewTTLTree[K nodeKey, V any](inner Tree[K, TTLEntry[V]], opts ...Option) TTLTree[K, V] {
+	o := newTreeOptions(treeOptions{now: time.Now}, opts)
+
+	t := &ttlTree[K, V]{
+		inner: inner,
+		index: NewCompoundTree[deadline, K](deadlineKey{}),
+		now:   o.now,
+	}
+	for k, e := range inner.All() {
+		if e.at.seq != 0 { // from another TTLTree
+			t.seq = max(t.seq, e.at.seq)
+			t.index.Insert(e.at, cloneKey(k))
+		}
+	}
+	return t
+}
+
+// WithClock makes NewTTLTree read the time from now instead of time.Now.
+func WithClock(now func() time.Time) Option {
+	return func(o *treeOptions) {
+		o.now = now
+	}
+}
+
+// TTLEntry is the value stored by a TTLTree in its inner tree. A zero
+// deadline never expires.
+type TTLEntry[V any] struct {
+	value V
+	at    deadline
+}
+
+// deadline is the key of the expiry index: the expiration time in Unix
+// nanoseconds and the sequence number of the insert, which tells apart the
+// entries expiring at the same time.
+type deadline struct {
+	at  int64
+	seq uint64
+}
+
+// deadlineKey orders the deadlines by time and then by sequence number.
+type deadlineKey struct{}
+
+func (dk deadlineKey) Transform(d deadline) ([]byte, []byte) {
+	b := binary.BigEndian.AppendUint64(make([]byte, 0, 16), uint64(d.at)^(1<<63))
+	b = binary.BigEndian.AppendUint64(b, d.seq)
+	return b, b
+}
+func (dk deadlineKey) Restore(b []byte) deadline {
+	return deadline{
+		at:  int64(binary.BigEndian.Uint64(b) ^ (1 << 63)),
+		seq: binary.BigEndian.Uint64(b[8:]),
+	}
+}
+
+type ttlTree[K nodeKey, V any] struct {
+	inner Tree[K, TTLEntry[V]]
+	index Tree[deadline, K]
+	now   func() time.Time
+	seq   uint64
+
+	onExpire func(K, V)
+}
+
+func (e TTLEntry[V]) expired(now int64) bool {
+	return e.at.seq != 0 && e.at.at <= now
+}
+
+func (t *ttlTree[K, V]) Insert(key K, val V) { t.insert(key, val, deadline{}) }
+
+func (t *ttlTree[K, V]) InsertWithTTL(key K, val V, ttl time.Duration) {
+	t.seq++
+	t.insert(key, val, deadline{at: t.now().Add(ttl).UnixNano(), seq: t.seq})
+}
+
+func (t *ttlTree[K, V]) insert(key K, val V, at deadline) {
+	if old, ok := t.inner.Search(key); ok && old.at.seq != 0 {
+		t.index.Delete(old.at)
+	}
+
+	t.inner.Insert(key, TTLEntry[V]{value: val, at: at})
+	if at.seq != 0 {
+		t.index.Insert(at, cloneKey(key))
+	}
+
+	t.Sweep(insertSweepBatch)
+}
+
+// cloneKey copies the slice keys kept by the expiry index.
+func cloneKey[K nodeKey](key K) K {
+	switch k := any(key).(type) {
+	case []byte:
+		return any(slices.Clone(k)).(K)
+	case []rune:
+		return any(slices.Clone(k)).(K)
+	}
+	return key
+}
+
+func (t *ttlTree[K, V]) Search(key K) (V, bool) {
+	e, ok := t.inner.Search(key)
+	if !ok || t.expire(key, e) {
+		var zero V
+		return zero, false
+	}
+	return e.value, true
+}
+
+func (t *ttlTree[K, V]) TTL(key K) (time.Duration, bool) {
+	e, ok := t.inner.Search(key)
+	if !ok || e.at.seq == 0 || t.expire(key, e) {
+		return 0, false
+	}
+	return time.Duration(e.at.at - t.now().UnixNano()), true
+}
+
+func (t *ttlTree[K, V]) Delete(key K) bool {
+	e, ok := t.inner.Search(key)
+	if !ok || t.expire(key, e) {
+		return false
+	}
+
+	if e.at.seq != 0 {
+		t.index.Delete(e.at)
+	}
+	return t.inner.Delete(key)
+}
+
+// expire removes the entry of key if it expired and reports whether it did.
+func (t *ttlTree[K, V]) expire(key K, e TTLEntry[V]) bool {
+	if !e.expired(t.now().UnixNano()) {
+		return false
+	}
+
+	t.index.Delete(e.at)
+	t.inner.Delete(key)
+	if t.onExpire != nil {
+		t.onExpire(key, e.value)
+	}
+	return true
+}
+
+func (t *ttlTree[K, V]) Sweep(max int) int {
+	now := t.now().UnixNano()
+
+	var expired []deadline
+	var keys []K
+	for d, k := range t.index.All() {
+		if d.at > now || (max > 0 && len(expired) == max) {
+			break
+		}
+		expired = append(expired, d)
+		keys = append(keys, k)
+	}
+
+	for i, d := range expired {
+		e, _ := t.inner.Search(keys[i])
+		t.index.Delete(d)
+		t.inner.Delete(keys[i])
+		if t.onExpire != nil {
+			t.onExpire(keys[i], e.value)
+		}
+	}
+	return len(expired)
+}
+
+func (t *ttlTree[K, V]) StartSweeper(interval time.Duration, batch int, mu sync.Locker) func() {
+	ticker := time.NewTicker(interval)
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+
+	go func() {
+		defer close(stopped)
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				mu.Lock()
+				t.Sweep(batch)
+				mu.Unlock()
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			ticker.Stop()
+			close(done)
+		})
+		<-stopped
+	}
+}
+
+func (t *ttlTree[K, V]) OnExpire(fn func(K, V)) { t.onExpire = fn }
+
+func (t *ttlTree[K, V]) Minimum() (K, V, bool) {
+	for k, v := range t.All() {
+		return k, v, true
+	}
+
+	var (
+		zeroK K
+		zeroV V
+	)
+	return zeroK, zeroV, false
+}
+
+func (t *ttlTree[K, V]) Maximum() (K, V, bool) {
+	for k, v := range t.Backward() {
+		return k, v, true
+	}
+
+	var (
+		zeroK K
+		zeroV V
+	)
+	return zeroK, zeroV, false
+}
+
+func (t *ttlTree[K, V]) All() iter.Seq2[K, V] { return t.live(t.inner.All(), 0) }
+
+func (t *ttlTree[K, V]) Backward() iter.Seq2[K, V] { return t.live(t.inner.Backward(), 0) }
+
+func (t *ttlTree[K, V]) Prefix(p K) iter.Seq2[K, V] { return t.live(t.inner.Prefix(p), 0) }
+
+func (t *ttlTree[K, V]) PrefixBackward(p K) iter.Seq2[K, V] {
+	return t.live(t.inner.PrefixBackward(p), 0)
+}
+
+func (t *ttlTree[K, V]) TopK(k uint) iter.Seq2[K, V] { return topK(t, k) }
+
+func (t *ttlTree[K, V]) BottomK(k uint) iter.Seq2[K, V] { return bottomK(t, k) }
+
+func (t *ttlTree[K, V]) Range(start, end K) iter.Seq2[K, V] {
+	return t.live(t.inner.Range(start, end), 0)
+}
+
+// RangeWith iterates over the entries within the bounds of the options, the
+// limit only counting the entries which didn't expire.
+func (t *ttlTree[K, V]) RangeWith(opts RangeOptions[K]) iter.Seq2[K, V] {
+	limit := opts.Limit
+	opts.Limit = 0
+	return t.live(t.inner.RangeWith(opts), limit)
+}
+
+// Size returns the number of entries, after removing the expired ones.
+func (t *ttlTree[K, V]) Size() int {
+	t.Sweep(0)
+	return t.inner.Size()
+}
+
+func (t *ttlTree[K, V]) Stats() Stats { return t.inner.Stats() }
+
+// live skips the expired entries of seq and stops after limit entries when
+// limit is positive. The expired entries are left for Sweep since the tree
+// can't be modified during the iteration.
+func (t *ttlTree[K, V]) live(seq iter.Seq2[K, TTLEntry[V]], limit int) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		now := t.now().UnixNano()
+		n := 0
+		for k, e := range seq {
+			if e.expired(now) {
+				continue
+			}
+
+			if !yield(k, e.value) {
+				return
+			}
+
+			n++
+			if n == limit {
+				return
+			}
+		}
+	}
+}
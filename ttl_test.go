@@ -0,0 +1,237 @@
+package art_test
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Clement-Jean/go-art"
+)
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time { return c.now }
+
+func (c *fakeClock) Advance(d time.Duration) { c.now = c.now.Add(d) }
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+}
+
+func TestTTLSearch(t *testing.T) {
+	clock := newFakeClock()
+	tr := art.NewTTLTree(art.NewAlphaSortedTree[string, art.TTLEntry[int]](), art.WithClock(clock.Now))
+
+	var expired []string
+	tr.OnExpire(func(k string, v int) { expired = append(expired, k) })
+
+	tr.InsertWithTTL("session:a", 1, time.Minute)
+	tr.InsertWithTTL("session:b", 2, 2*time.Minute)
+	tr.Insert("config", 3)
+
+	if v, ok := tr.Search("session:a"); !ok || v != 1 {
+		t.Fatalf("expected session:a to be found with 1, got %d", v)
+	}
+	if ttl, ok := tr.TTL("session:b"); !ok || ttl != 2*time.Minute {
+		t.Fatalf("expected a TTL of 2m, got %v", ttl)
+	}
+	if _, ok := tr.TTL("config"); ok {
+		t.Fatal("expected config to never expire")
+	}
+
+	clock.Advance(time.Minute)
+
+	if _, ok := tr.Search("session:a"); ok {
+		t.Fatal("expected session:a to be expired")
+	}
+	if !slices.Equal([]string{"session:a"}, expired) {
+		t.Fatalf("expected session:a to be expired, got %v", expired)
+	}
+	if tr.Delete("session:a") {
+		t.Fatal("expected Delete to miss the expired session:a")
+	}
+
+	clock.Advance(time.Hour)
+
+	if v, ok := tr.Search("config"); !ok || v != 3 {
+		t.Fatalf("expected config to be found with 3, got %d", v)
+	}
+	if tr.Size() != 1 {
+		t.Fatalf("expected 1 entry, got %d", tr.Size())
+	}
+	if !slices.Equal([]string{"session:a", "session:b"}, expired) {
+		t.Fatalf("expected session:a and session:b to be expired, got %v", expired)
+	}
+}
+
+func TestTTLIteration(t *testing.T) {
+	clock := newFakeClock()
+	tr := art.NewTTLTree(art.NewAlphaSortedTree[string, art.TTLEntry[int]](), art.WithClock(clock.Now))
+
+	for i := range 10 {
+		tr.InsertWithTTL(fmt.Sprintf("k%d", i), i, time.Duration(i%2+1)*time.Minute)
+	}
+	clock.Advance(time.Minute)
+
+	var got []string
+	for k := range tr.All() {
+		got = append(got, k)
+	}
+	if expected := []string{"k1", "k3", "k5", "k7", "k9"}; !slices.Equal(expected, got) {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+
+	got = got[:0]
+	for k := range tr.RangeWith(art.RangeOptions[string]{
+		Start:   art.Inclusive("k0"),
+		End:     art.Inclusive("k6"),
+		Reverse: true,
+		Limit:   2,
+	}) {
+		got = append(got, k)
+	}
+	if expected := []string{"k5", "k3"}; !slices.Equal(expected, got) {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+
+	got = got[:0]
+	for k := range tr.BottomK(2) {
+		got = append(got, k)
+	}
+	if expected := []string{"k1", "k3"}; !slices.Equal(expected, got) {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+
+	if k, _, ok := tr.Minimum(); !ok || k != "k1" {
+		t.Fatalf("expected minimum k1, got %s", k)
+	}
+	if k, _, ok := tr.Maximum(); !ok || k != "k9" {
+		t.Fatalf("expected maximum k9, got %s", k)
+	}
+}
+
+func TestTTLSweep(t *testing.T) {
+	clock := newFakeClock()
+	tr := art.NewTTLTree(art.NewAlphaSortedTree[[]byte, art.TTLEntry[int]](), art.WithClock(clock.Now))
+
+	expired := 0
+	tr.OnExpire(func(k []byte, v int) { expired++ })
+
+	for i := range 100 {
+		tr.InsertWithTTL([]byte(fmt.Sprintf("token:%03d", i)), i, time.Duration(i+1)*time.Second)
+	}
+
+	// replacing an entry moves its deadline
+	tr.InsertWithTTL([]byte("token:000"), 0, time.Hour)
+
+	clock.Advance(50 * time.Second)
+
+	if n := tr.Sweep(10); n != 10 {
+		t.Fatalf("expected 10 entries to be swept, got %d", n)
+	}
+	if n := tr.Sweep(0); n != 39 {
+		t.Fatalf("expected 39 entries to be swept, got %d", n)
+	}
+	if n := tr.Sweep(0); n != 0 {
+		t.Fatalf("expected nothing to be swept, got %d", n)
+	}
+	if expired != 49 {
+		t.Fatalf("expected 49 expired entries, got %d", expired)
+	}
+
+	if v, ok := tr.Search([]byte("token:000")); !ok || v != 0 {
+		t.Fatalf("expected token:000 to be found with 0, got %d", v)
+	}
+	if tr.Size() != 51 {
+		t.Fatalf("expected 51 entries, got %d", tr.Size())
+	}
+}
+
+func TestTTLInsertSweeps(t *testing.T) {
+	clock := newFakeClock()
+	tr := art.NewTTLTree(art.NewAlphaSortedTree[string, art.TTLEntry[int]](), art.WithClock(clock.Now))
+
+	for i := range 10 {
+		tr.InsertWithTTL(fmt.Sprint(i), i, time.Second)
+	}
+	clock.Advance(time.Second)
+
+	for i := range 5 {
+		tr.Insert(fmt.Sprint("new", i), i)
+	}
+
+	if s := tr.Stats(); s.Leaves != 5 {
+		t.Fatalf("expected the 5 inserts to sweep 10 entries, got %d leaves", s.Leaves)
+	}
+}
+
+func TestTTLSweeper(t *testing.T) {
+	var mu sync.Mutex
+	clock := newFakeClock()
+	tr := art.NewTTLTree(art.NewAlphaSortedTree[string, art.TTLEntry[int]](), art.WithClock(clock.Now))
+
+	expired := 0
+	tr.OnExpire(func(k string, v int) { expired++ })
+
+	for i := range 20 {
+		tr.InsertWithTTL(fmt.Sprint("token:", i), i, time.Second)
+	}
+	tr.Insert("config", 0)
+
+	stop := tr.StartSweeper(time.Millisecond, 3, &mu)
+
+	mu.Lock()
+	clock.Advance(time.Second)
+	mu.Unlock()
+
+	for deadline := time.Now().Add(5 * time.Second); ; {
+		mu.Lock()
+		n := expired
+		mu.Unlock()
+
+		if n == 20 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected the sweeper to expire 20 entries, got %d", n)
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	stop()
+	stop() // stopping twice is fine
+
+	tr.InsertWithTTL("late", 1, time.Second)
+	clock.Advance(time.Second)
+	time.Sleep(10 * time.Millisecond)
+
+	if s := tr.Stats(); s.Leaves != 2 || expired != 20 {
+		t.Fatalf("expected the stopped sweeper to leave the expired entry, got %d leaves and %d expired", s.Leaves, expired)
+	}
+}
+
+func TestTTLNumericKeys(t *testing.T) {
+	clock := newFakeClock()
+	tr := art.NewTTLTree(art.NewUnsignedBinaryTree[uint64, art.TTLEntry[string]](), art.WithClock(clock.Now))
+
+	for i := range uint64(10) {
+		tr.InsertWithTTL(i, fmt.Sprint(i), time.Duration(i+1)*time.Second)
+	}
+	clock.Advance(5 * time.Second)
+
+	if n := tr.Sweep(0); n != 5 {
+		t.Fatalf("expected 5 entries to be swept, got %d", n)
+	}
+
+	var got []uint64
+	for k := range tr.Range(0, 7) {
+		got = append(got, k)
+	}
+	if expected := []uint64{5, 6, 7}; !slices.Equal(expected, got) {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+}
@@ -0,0 +1,299 @@
+// Package tuple implements an order-preserving encoding of tuples, compatible
+// with the FoundationDB tuple layer for the supported types.
+//
+// Comparing two packed tuples byte by byte gives the same result as comparing
+// the tuples element by element, and a packed tuple is a prefix of the packed
+// tuples it is a prefix of. Packed tuples are thus not prefix-free: a tuple
+// packed as the only element of a Tuple is nested and terminated instead, as
+// art.TupleKey does for compound keys.
+package tuple
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"math/bits"
+)
+
+// Tuple is a list of elements. The supported element types are nil, []byte,
+// string, bool, the signed and unsigned integers, float32, float64 and
+// Tuple.
+type Tuple []any
+
+// type codes
+const (
+	nilCode     = 0x00
+	bytesCode   = 0x01
+	stringCode  = 0x02
+	nestedCode  = 0x05
+	intZeroCode = 0x14
+	float32Code = 0x20
+	float64Code = 0x21
+	falseCode   = 0x26
+	trueCode    = 0x27
+
+	escapedByte = 0xFF
+)
+
+// Pack encodes the elements as a tuple. It panics on an unsupported type.
+func Pack(elems ...any) []byte {
+	return Tuple(elems).Pack()
+}
+
+// Pack encodes the tuple. It panics on an unsupported type.
+func (t Tuple) Pack() []byte {
+	return t.AppendPack(nil)
+}
+
+// AppendPack appends the encoding of the tuple to dst and returns the
+// extended buffer. It panics on an unsupported type.
+func (t Tuple) AppendPack(dst []byte) []byte {
+	for _, e := range t {
+		dst = appendElem(dst, e, false)
+	}
+	return dst
+}
+
+func appendElem(dst []byte, e any, nested bool) []byte {
+	switch e := e.(type) {
+	case nil:
+		if nested {
+			return append(dst, nilCode, escapedByte)
+		}
+		return append(dst, nilCode)
+	case []byte:
+		return appendEscaped(append(dst, bytesCode), e)
+	case string:
+		return appendEscaped(append(dst, stringCode), []byte(e))
+	case bool:
+		if e {
+			return append(dst, trueCode)
+		}
+		return append(dst, falseCode)
+	case int:
+		return appendInt(dst, int64(e))
+	case int8:
+		return appendInt(dst, int64(e))
+	case int16:
+		return appendInt(dst, int64(e))
+	case int32:
+		return appendInt(dst, int64(e))
+	case int64:
+		return appendInt(dst, e)
+	case uint:
+		return appendUint(dst, uint64(e))
+	case uint8:
+		return appendUint(dst, uint64(e))
+	case uint16:
+		return appendUint(dst, uint64(e))
+	case uint32:
+		return appendUint(dst, uint64(e))
+	case uint64:
+		return appendUint(dst, e)
+	case float32:
+		u := math.Float32bits(e)
+		if u&(1<<31) != 0 {
+			u = ^u
+		} else {
+			u ^= 1 << 31
+		}
+		return binary.BigEndian.AppendUint32(append(dst, float32Code), u)
+	case float64:
+		u := math.Float64bits(e)
+		if u&(1<<63) != 0 {
+			u = ^u
+		} else {
+			u ^= 1 << 63
+		}
+		return binary.BigEndian.AppendUint64(append(dst, float64Code), u)
+	case Tuple:
+		dst = append(dst, nestedCode)
+		for _, ne := range e {
+			dst = appendElem(dst, ne, true)
+		}
+		return append(dst, 0x00)
+	}
+
+	panic(fmt.Sprintf("tuple: unsupported type %T", e))
+}
+
+// appendEscaped appends b with every 0x00 escaped as 0x00 0xFF, followed by
+// the 0x00 terminator.
+func appendEscaped(dst, b []byte) []byte {
+	for _, c := range b {
+		dst = append(dst, c)
+		if c == 0x00 {
+			dst = append(dst, escapedByte)
+		}
+	}
+	return append(dst, 0x00)
+}
+
+// appendUint appends n with the minimal number of big-endian bytes.
+func appendUint(dst []byte, n uint64) []byte {
+	l := (bits.Len64(n) + 7) / 8
+	dst = append(dst, byte(intZeroCode+l))
+	for i := l - 1; i >= 0; i-- {
+		dst = append(dst, byte(n>>(8*i)))
+	}
+	return dst
+}
+
+// appendInt appends n, storing negative numbers as the one's complement of
+// their magnitude so that they sort before the positive ones.
+func appendInt(dst []byte, n int64) []byte {
+	if n >= 0 {
+		return appendUint(dst, uint64(n))
+	}
+
+	m := uint64(-n)
+	l := (bits.Len64(m) + 7) / 8
+	m = ^m
+	dst = append(dst, byte(intZeroCode-l))
+	for i := l - 1; i >= 0; i-- {
+		dst = append(dst, byte(m>>(8*i)))
+	}
+	return dst
+}
+
+// ErrInvalid is returned by Unpack when the input is not a packed tuple.
+var ErrInvalid = errors.New("tuple: invalid encoding")
+
+// Unpack decodes a packed tuple. Integers are returned as int64, or uint64
+// when they don't fit in an int64.
+func Unpack(b []byte) (Tuple, error) {
+	var t Tuple
+
+	for len(b) > 0 {
+		e, n, err := decodeElem(b, false)
+		if err != nil {
+			return nil, err
+		}
+		t = append(t, e)
+		b = b[n:]
+	}
+	return t, nil
+}
+
+// decodeElem decodes the element at the start of b and returns it with the
+// number of bytes it took.
+func decodeElem(b []byte, nested bool) (any, int, error) {
+	code := b[0]
+
+	switch {
+	case code == nilCode:
+		if nested {
+			if len(b) < 2 || b[1] != escapedByte {
+				return nil, 0, ErrInvalid
+			}
+			return nil, 2, nil
+		}
+		return nil, 1, nil
+	case code == bytesCode:
+		s, n, err := unescape(b[1:])
+		return s, n + 1, err
+	case code == stringCode:
+		s, n, err := unescape(b[1:])
+		return string(s), n + 1, err
+	case code == falseCode:
+		return false, 1, nil
+	case code == trueCode:
+		return true, 1, nil
+	case code >= intZeroCode-8 && code <= intZeroCode+8:
+		return decodeInt(b)
+	case code == float32Code:
+		if len(b) < 5 {
+			return nil, 0, ErrInvalid
+		}
+		u := binary.BigEndian.Uint32(b[1:])
+		if u&(1<<31) != 0 {
+			u ^= 1 << 31
+		} else {
+			u = ^u
+		}
+		return math.Float32frombits(u), 5, nil
+	case code == float64Code:
+		if len(b) < 9 {
+			return nil, 0, ErrInvalid
+		}
+		u := binary.BigEndian.Uint64(b[1:])
+		if u&(1<<63) != 0 {
+			u ^= 1 << 63
+		} else {
+			u = ^u
+		}
+		return math.Float64frombits(u), 9, nil
+	case code == nestedCode:
+		t := Tuple{}
+		i := 1
+		for {
+			if i == len(b) {
+				return nil, 0, ErrInvalid
+			}
+			if b[i] == 0x00 && (i+1 == len(b) || b[i+1] != escapedByte) {
+				return t, i + 1, nil
+			}
+
+			e, n, err := decodeElem(b[i:], true)
+			if err != nil {
+				return nil, 0, err
+			}
+			t = append(t, e)
+			i += n
+		}
+	}
+
+	return nil, 0, fmt.Errorf("%w: unknown type code 0x%02x", ErrInvalid, code)
+}
+
+func decodeInt(b []byte) (any, int, error) {
+	code := int(b[0])
+	neg := code < intZeroCode
+	l := code - intZeroCode
+	if neg {
+		l = -l
+	}
+
+	if len(b) < l+1 {
+		return nil, 0, ErrInvalid
+	}
+
+	var u uint64
+	for _, c := range b[1 : l+1] {
+		u = u<<8 | uint64(c)
+	}
+
+	if neg {
+		u = ^u
+		if l < 8 {
+			u &= 1<<(8*l) - 1
+		}
+		return -int64(u), l + 1, nil
+	}
+	if u > math.MaxInt64 {
+		return u, l + 1, nil
+	}
+	return int64(u), l + 1, nil
+}
+
+// unescape decodes an escaped byte string up to its 0x00 terminator and
+// returns it with the number of bytes it took.
+func unescape(b []byte) ([]byte, int, error) {
+	s := []byte{}
+
+	for i := 0; i < len(b); i++ {
+		if b[i] != 0x00 {
+			s = append(s, b[i])
+			continue
+		}
+
+		if i+1 < len(b) && b[i+1] == escapedByte {
+			s = append(s, 0x00)
+			i++
+			continue
+		}
+		return s, i + 1, nil
+	}
+	return nil, 0, ErrInvalid
+}
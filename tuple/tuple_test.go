@@ -0,0 +1,138 @@
+package tuple_test
+
+import (
+	"bytes"
+	"math"
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/Clement-Jean/go-art/tuple"
+)
+
+func TestRoundTrip(t *testing.T) {
+	tests := []tuple.Tuple{
+		{},
+		{nil},
+		{[]byte{}, []byte("a\x00b"), ""},
+		{"hello", "wo\x00rld"},
+		{true, false},
+		{int64(0), int64(1), int64(-1), int64(255), int64(-256), int64(math.MaxInt64), int64(math.MinInt64)},
+		{uint64(math.MaxUint64)},
+		{float32(1.5), float32(-2), math.Inf(-1), 0.0, math.Copysign(0, -1)},
+		{tuple.Tuple{nil, "a", tuple.Tuple{}}, nil, "b"},
+	}
+
+	for _, tt := range tests {
+		got, err := tuple.Unpack(tt.Pack())
+		if err != nil {
+			t.Fatalf("%v: %v", tt, err)
+		}
+
+		if len(tt) == 0 && len(got) == 0 {
+			continue
+		}
+		if !reflect.DeepEqual(tt, got) {
+			t.Fatalf("expected %#v, got %#v", tt, got)
+		}
+	}
+}
+
+func TestIntWidths(t *testing.T) {
+	got, err := tuple.Unpack(tuple.Pack(int8(-3), uint16(300), int(7), uint8(0)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected := (tuple.Tuple{int64(-3), int64(300), int64(7), int64(0)}); !reflect.DeepEqual(expected, got) {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+}
+
+func TestOrder(t *testing.T) {
+	// sorted in tuple order
+	tuples := []tuple.Tuple{
+		{nil},
+		{[]byte("a")},
+		{[]byte("a"), nil},
+		{[]byte("a\x00")},
+		{"", int64(1)},
+		{"a"},
+		{"a", int64(-1)},
+		{"a", int64(0)},
+		{"a", int64(2)},
+		{"ab"},
+		{"b"},
+		{tuple.Tuple{}},
+		{tuple.Tuple{nil}},
+		{tuple.Tuple{"a"}},
+		{int64(math.MinInt64)},
+		{int64(-256)},
+		{int64(-255)},
+		{int64(-1)},
+		{int64(0)},
+		{int64(1)},
+		{int64(255)},
+		{int64(256)},
+		{uint64(math.MaxUint64)},
+		{float32(-1)},
+		{float32(1)},
+		{math.Inf(-1)},
+		{-1.5},
+		{math.Copysign(0, -1)},
+		{0.0},
+		{math.Inf(1)},
+		{false},
+		{true},
+	}
+
+	var packed [][]byte
+	for _, tt := range tuples {
+		packed = append(packed, tt.Pack())
+	}
+
+	if !slices.IsSortedFunc(packed, bytes.Compare) {
+		for i := 1; i < len(packed); i++ {
+			if bytes.Compare(packed[i-1], packed[i]) >= 0 {
+				t.Fatalf("expected %v < %v", tuples[i-1], tuples[i])
+			}
+		}
+	}
+}
+
+func TestPrefix(t *testing.T) {
+	prefix := tuple.Pack("users", int64(42))
+	key := tuple.Pack("users", int64(42), "alice")
+	other := tuple.Pack("users", int64(420))
+
+	if !bytes.HasPrefix(key, prefix) {
+		t.Fatalf("expected %x to start with %x", key, prefix)
+	}
+	if bytes.HasPrefix(other, prefix) {
+		t.Fatalf("expected %x not to start with %x", other, prefix)
+	}
+}
+
+func TestUnpackInvalid(t *testing.T) {
+	for _, b := range [][]byte{
+		{0x02, 'a'},        // missing terminator
+		{0x16, 0x01},       // truncated int
+		{0x21, 0x00},       // truncated float
+		{0x05, 0x02, 0x00}, // unterminated nested tuple
+		{0x40},             // unknown code
+	} {
+		if _, err := tuple.Unpack(b); err == nil {
+			t.Fatalf("expected an error for %x", b)
+		}
+	}
+}
+
+func TestPackUnsupported(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected a panic")
+		}
+	}()
+
+	tuple.Pack(struct{}{})
+}
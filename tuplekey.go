@@ -0,0 +1,54 @@
+package art
+
+import "github.com/Clement-Jean/go-art/tuple"
+
+// TupleKey orders keys by their tuple encoding (see package tuple), which
+// keeps the order of every element of the tuple, including variable-length
+// ones. ToTuple and FromTuple convert the keys to and from tuples and can be
+// left nil when K is tuple.Tuple.
+//
+// The tuple is packed as a nested tuple, which is terminated, so that a
+// shorter tuple doesn't encode to a prefix of the longer ones. Prefix
+// searches still match the tuples starting with the elements of the prefix.
+type TupleKey[K any] struct {
+	ToTuple   func(K) tuple.Tuple
+	FromTuple func(tuple.Tuple) K
+}
+
+func (tk TupleKey[K]) Transform(k K) ([]byte, []byte) {
+	var t tuple.Tuple
+	if tk.ToTuple != nil {
+		t = tk.ToTuple(k)
+	} else {
+		t = any(k).(tuple.Tuple)
+	}
+
+	b := tuple.Tuple{t}.Pack()
+	return b, b
+}
+func (tk TupleKey[K]) Restore(b []byte) K {
+	t, err := tuple.Unpack(b)
+	if err != nil {
+		panic(err)
+	}
+	if len(t) != 1 {
+		panic(tuple.ErrInvalid)
+	}
+
+	nested, ok := t[0].(tuple.Tuple)
+	if !ok {
+		panic(tuple.ErrInvalid)
+	}
+	if tk.FromTuple != nil {
+		return tk.FromTuple(nested)
+	}
+	return any(nested).(K)
+}
+
+// transformPrefix drops the terminator of the nested tuple.
+func (tk TupleKey[K]) transformPrefix(p K) []byte {
+	_, b := tk.Transform(p)
+	return b[:len(b)-1]
+}
+
+var _ BinaryComparableKey[tuple.Tuple] = TupleKey[tuple.Tuple]{}
@@ -1,5 +1,38 @@
 package art
 
-func NewUnsignedBinaryTree[K uints, V any]() Tree[K, V] {
-	return &unsignedSortedTree[K, V]{}
+func NewUnsignedBinaryTree[K uints, V any](opts ...Option) Tree[K, V] {
+	return &unsignedSortedTree[K, V]{
+		unsignedCodec: unsignedCodec[K]{opts: newTreeOptions(treeOptions{}, opts)},
+	}
+}
+
+// NewUnsignedBinarySet returns a set ordering the keys like
+// NewUnsignedBinaryTree.
+func NewUnsignedBinarySet[K uints](opts ...Option) Set[K] {
+	t := &unsignedSortedTree[K, struct{}]{
+		unsignedCodec: unsignedCodec[K]{opts: newTreeOptions(treeOptions{}, opts)},
+	}
+	return &keySet[K]{inner: t, encode: t.encodeKey}
+}
+
+// unsignedCodec encodes the keys of the unsigned trees.
+type unsignedCodec[K uints] struct {
+	bck  UnsignedBinaryKey[K]
+	opts treeOptions
+}
+
+func (c *unsignedCodec[K]) encodeKey(key K) []byte {
+	_, keyS := c.bck.Transform(key)
+	if c.opts.descending {
+		invert(keyS, keyS)
+	}
+	return keyS
+}
+
+func (c *unsignedCodec[K]) decodeKey(b []byte) K {
+	if c.opts.descending {
+		var buf [8]byte
+		return c.bck.Restore(invert(buf[:len(b)], b))
+	}
+	return c.bck.Restore(b)
 }
@@ -2,6 +2,7 @@ package art_test
 
 import (
 	"fmt"
+	"math/rand/v2"
 	"slices"
 	"testing"
 
@@ -101,3 +102,43 @@ func TestUnsignedRange(t *testing.T) {
 		})
 	}
 }
+
+func TestUnsignedRangeRandom(t *testing.T) {
+	r := rand.New(rand.NewPCG(1, 2))
+	tr := art.NewUnsignedBinaryTree[uint64, int]()
+
+	var keys []uint64
+	for i := range 100_000 {
+		k := r.Uint64N(1 << 40)
+		tr.Insert(k, i)
+		keys = append(keys, k)
+	}
+
+	slices.Sort(keys)
+	keys = slices.Compact(keys)
+
+	for range 100 {
+		start, end := r.Uint64N(1<<40), r.Uint64N(1<<40)
+		if start > end {
+			start, end = end, start
+		}
+		if r.IntN(2) == 0 { // narrow range
+			end = start + r.Uint64N(1<<24)
+		}
+
+		lo, _ := slices.BinarySearch(keys, start)
+		hi, found := slices.BinarySearch(keys, end)
+		if found {
+			hi++
+		}
+
+		var got []uint64
+		for k, _ := range tr.Range(start, end) {
+			got = append(got, k)
+		}
+
+		if !slices.Equal(keys[lo:hi], got) {
+			t.Fatalf("range [%d, %d]: expected %d keys, got %d", start, end, hi-lo, len(got))
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package art
+
+import (
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// WithUTF16Order makes NewAlphaSortedTree order the keys by their UTF-16 code
+// units, as Java's String.compareTo and JavaScript's < do, instead of by code
+// points. The two orders only differ for the runes above U+FFFF, which come
+// before U+E000..U+FFFF in UTF-16. The keys must be valid UTF-8.
+func WithUTF16Order() Option {
+	return func(o *treeOptions) {
+		o.utf16 = true
+	}
+}
+
+// CodePointOrderKey orders text by code points, which is the byte order of
+// UTF-8. Like EscapedBinaryKey, the keys are escaped and terminated, so it can
+// be part of a compound key, but it also accepts []rune keys.
+type CodePointOrderKey[K chars | []rune] struct{}
+
+func (cpk CodePointOrderKey[K]) Transform(k K) ([]byte, []byte) {
+	s := string(k)
+	b := appendEscaped(make([]byte, 0, len(s)+2), []byte(s))
+	b = append(b, escapeByte, endByte)
+	return b, b
+}
+func (cpk CodePointOrderKey[K]) Restore(b []byte) K {
+	k, _ := cpk.RestorePrefix(b)
+	return k
+}
+
+// RestorePrefix restores the key encoded at the start of b and returns it
+// with the number of bytes it took.
+func (cpk CodePointOrderKey[K]) RestorePrefix(b []byte) (K, int) {
+	k, n := unescape(b)
+	return K(string(k)), n
+}
+
+var _ BinaryComparableKey[[]rune] = CodePointOrderKey[[]rune]{}
+
+// UTF16OrderKey orders text by UTF-16 code units, like WithUTF16Order. The
+// keys are escaped and terminated as with CodePointOrderKey.
+type UTF16OrderKey[K chars | []rune] struct{}
+
+func (uok UTF16OrderKey[K]) Transform(k K) ([]byte, []byte) {
+	s := utf16Order([]byte(string(k)))
+	b := appendEscaped(make([]byte, 0, len(s)+2), s)
+	b = append(b, escapeByte, endByte)
+	return b, b
+}
+func (uok UTF16OrderKey[K]) Restore(b []byte) K {
+	k, _ := uok.RestorePrefix(b)
+	return k
+}
+
+// RestorePrefix restores the key encoded at the start of b and returns it
+// with the number of bytes it took.
+func (uok UTF16OrderKey[K]) RestorePrefix(b []byte) (K, int) {
+	k, n := unescape(b)
+	return K(string(codePointOrder(k))), n
+}
+
+var _ BinaryComparableKey[[]rune] = UTF16OrderKey[[]rune]{}
+
+// utf16Order re-encodes the runes above U+FFFF of b as two 3-byte sequences,
+// one per surrogate (CESU-8). The bytes are then ordered like the UTF-16 code
+// units since the surrogates are below U+E000. b is returned as is when it
+// has no such rune.
+func utf16Order(b []byte) []byte {
+	i := 0
+	for i < len(b) && b[i] < 0xF0 {
+		i++
+	}
+	if i == len(b) {
+		return b
+	}
+
+	dst := make([]byte, 0, len(b)+len(b)/2)
+	dst = append(dst, b[:i]...)
+	for i < len(b) {
+		r, n := utf8.DecodeRune(b[i:])
+		if n == 4 {
+			hi, lo := utf16.EncodeRune(r)
+			dst = appendSurrogate(dst, hi)
+			dst = appendSurrogate(dst, lo)
+		} else {
+			dst = append(dst, b[i:i+n]...)
+		}
+		i += n
+	}
+	return dst
+}
+
+// codePointOrder is the inverse of utf16Order.
+func codePointOrder(b []byte) []byte {
+	i := indexSurrogatePair(b)
+	if i < 0 {
+		return b
+	}
+
+	dst := make([]byte, 0, len(b))
+	for ; i >= 0; i = indexSurrogatePair(b) {
+		dst = append(dst, b[:i]...)
+		r := utf16.DecodeRune(decodeSurrogate(b[i:]), decodeSurrogate(b[i+3:]))
+		dst = utf8.AppendRune(dst, r)
+		b = b[i+6:]
+	}
+	return append(dst, b...)
+}
+
+func appendSurrogate(dst []byte, r rune) []byte {
+	return append(dst, 0xE0|byte(r>>12), 0x80|byte(r>>6)&0x3F, 0x80|byte(r)&0x3F)
+}
+
+func decodeSurrogate(b []byte) rune {
+	return rune(b[0]&0x0F)<<12 | rune(b[1]&0x3F)<<6 | rune(b[2]&0x3F)
+}
+
+// indexSurrogatePair returns the index of the first high surrogate followed by
+// a low surrogate encoded by utf16Order, or -1.
+func indexSurrogatePair(b []byte) int {
+	for i := 0; i+6 <= len(b); i++ {
+		if b[i] == 0xED && b[i+1]&0xF0 == 0xA0 && b[i+2]&0xC0 == 0x80 &&
+			b[i+3] == 0xED && b[i+4]&0xF0 == 0xB0 && b[i+5]&0xC0 == 0x80 {
+			return i
+		}
+	}
+	return -1
+}
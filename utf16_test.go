@@ -0,0 +1,160 @@
+package art_test
+
+import (
+	"math/rand/v2"
+	"slices"
+	"strings"
+	"testing"
+	"unicode/utf16"
+
+	"github.com/Clement-Jean/go-art"
+)
+
+// utf16Compare compares like Java's String.compareTo.
+func utf16Compare(a, b string) int {
+	return slices.Compare(utf16.Encode([]rune(a)), utf16.Encode([]rune(b)))
+}
+
+func randomText(r *rand.Rand) string {
+	alphabet := []rune{'a', 'z', 'é', '中', '\ud7ff', '\ue000', '\uff61', '\U0001f600', '\U00010000', '\U0010ffff'}
+
+	rs := make([]rune, r.IntN(6))
+	for i := range rs {
+		rs[i] = alphabet[r.IntN(len(alphabet))]
+	}
+	return string(rs)
+}
+
+func TestAlphaRuneKeys(t *testing.T) {
+	tr := art.NewAlphaSortedTree[[]rune, int]()
+
+	keys := []string{"éclair", "eclair", "😀", "中文", "e"}
+	for i, k := range keys {
+		tr.Insert([]rune(k), i)
+	}
+
+	if v, ok := tr.Search([]rune("中文")); !ok || v != 3 {
+		t.Fatalf("expected 中文 to be found with 3, got %d", v)
+	}
+
+	var got []string
+	for k := range tr.Prefix([]rune("e")) {
+		got = append(got, string(k))
+	}
+	if expected := []string{"e", "eclair"}; !slices.Equal(expected, got) {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+
+	got = got[:0]
+	for k := range tr.All() {
+		got = append(got, string(k))
+	}
+	if expected := []string{"e", "eclair", "éclair", "中文", "😀"}; !slices.Equal(expected, got) {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+}
+
+func TestAlphaUTF16Order(t *testing.T) {
+	r := rand.New(rand.NewPCG(1, 2))
+
+	codePoints := art.NewAlphaSortedTree[string, int]()
+	codeUnits := art.NewAlphaSortedTree[string, int](art.WithUTF16Order())
+	runes := art.NewAlphaSortedTree[[]rune, int](art.WithUTF16Order())
+
+	var keys []string
+	for range 1000 {
+		k := randomText(r)
+		keys = append(keys, k)
+		codePoints.Insert(k, 0)
+		codeUnits.Insert(k, 0)
+		runes.Insert([]rune(k), 0)
+	}
+	slices.Sort(keys)
+	keys = slices.Compact(keys)
+
+	var got []string
+	for k := range codePoints.All() {
+		got = append(got, k)
+	}
+	if !slices.Equal(keys, got) {
+		t.Fatalf("expected code point order %q, got %q", keys, got)
+	}
+
+	slices.SortFunc(keys, utf16Compare)
+
+	got = got[:0]
+	for k := range codeUnits.All() {
+		got = append(got, k)
+	}
+	if !slices.Equal(keys, got) {
+		t.Fatalf("expected code unit order %q, got %q", keys, got)
+	}
+
+	got = got[:0]
+	for k := range runes.All() {
+		got = append(got, string(k))
+	}
+	if !slices.Equal(keys, got) {
+		t.Fatalf("expected code unit order %q, got %q", keys, got)
+	}
+}
+
+func TestAlphaUTF16OrderPrefix(t *testing.T) {
+	tr := art.NewAlphaSortedTree[string, int](art.WithUTF16Order())
+
+	keys := []string{"a\U0001f600b", "a\U0001f600", "a\U0001f601", "a\uff61", "b"}
+	for i, k := range keys {
+		tr.Insert(k, i)
+	}
+
+	var got []string
+	for k := range tr.Prefix("a\U0001f600") {
+		got = append(got, k)
+	}
+	if expected := []string{"a\U0001f600", "a\U0001f600b"}; !slices.Equal(expected, got) {
+		t.Fatalf("expected %q, got %q", expected, got)
+	}
+
+	got = got[:0]
+	for k := range tr.Range("a", "a\uffff") {
+		got = append(got, k)
+	}
+	if expected := []string{"a\U0001f600", "a\U0001f600b", "a\U0001f601", "a\uff61"}; !slices.Equal(expected, got) {
+		t.Fatalf("expected %q, got %q", expected, got)
+	}
+}
+
+func TestTextOrderKeys(t *testing.T) {
+	tests := []struct {
+		name  string
+		key   art.BinaryComparableKey[[]rune]
+		order func(a, b string) int
+	}{
+		{"code points", art.CodePointOrderKey[[]rune]{}, strings.Compare},
+		{"code units", art.UTF16OrderKey[[]rune]{}, utf16Compare},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := rand.New(rand.NewPCG(3, 4))
+			tr := art.NewCompoundTree[[]rune, int](tt.key)
+
+			var keys []string
+			for range 1000 {
+				k := randomText(r) + "\x00" + randomText(r)
+				keys = append(keys, k)
+				tr.Insert([]rune(k), 0)
+			}
+			slices.SortFunc(keys, tt.order)
+			keys = slices.Compact(keys)
+
+			var got []string
+			for k := range tr.All() {
+				got = append(got, string(k))
+			}
+			if !slices.Equal(keys, got) {
+				t.Fatalf("expected %q, got %q", keys, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package art
+
+import (
+	"encoding/binary"
+	"time"
+)
+
+// NewUUIDTree returns a tree of 16-byte keys such as UUIDs. The keys are
+// stored in the leaves instead of being referenced from them.
+func NewUUIDTree[K ~[16]byte, V any](opts ...Option) Tree[K, V] {
+	return &uuidSortedTree[K, V]{
+		uuidCodec: uuidCodec[K]{opts: newTreeOptions(treeOptions{}, opts)},
+	}
+}
+
+// NewUUIDSet returns a set of 16-byte keys stored in the leaves, like
+// NewUUIDTree.
+func NewUUIDSet[K ~[16]byte](opts ...Option) Set[K] {
+	t := &uuidSortedTree[K, struct{}]{
+		uuidCodec: uuidCodec[K]{opts: newTreeOptions(treeOptions{}, opts)},
+	}
+	return &keySet[K]{inner: t, encode: t.encodeKey}
+}
+
+// uuidCodec encodes the keys of the UUID trees.
+type uuidCodec[K ~[16]byte] struct {
+	bck  FixedBytesKey[K]
+	opts treeOptions
+}
+
+func (c *uuidCodec[K]) encodeKey(key K) []byte {
+	_, keyS := c.bck.Transform(key)
+	return keyS
+}
+
+func (c *uuidCodec[K]) decodeKey(b []byte) K {
+	return c.bck.Restore(b)
+}
+
+// UUIDv7Bounds returns the smallest and the largest UUIDv7 generated between
+// from and to, at millisecond precision. They can be used with Range to
+// iterate over the UUIDs of a time window.
+func UUIDv7Bounds[K ~[16]byte](from, to time.Time) (K, K) {
+	var lo, hi K
+
+	putUUIDv7Time(lo[:], from)
+	lo[6] = 0x70 // version
+	lo[8] = 0x80 // variant
+
+	putUUIDv7Time(hi[:], to)
+	for i := 6; i < len(hi); i++ {
+		hi[i] = 0xff
+	}
+	hi[6] = 0x7f
+	hi[8] = 0xbf
+	return lo, hi
+}
+
+// UUIDv7Time returns the creation time stored in a UUIDv7.
+func UUIDv7Time[K ~[16]byte](u K) time.Time {
+	var b [8]byte
+	copy(b[2:], u[:6])
+	return time.UnixMilli(int64(binary.BigEndian.Uint64(b[:])))
+}
+
+// putUUIDv7Time stores the 48-bit Unix timestamp in milliseconds of t.
+func putUUIDv7Time(b []byte, t time.Time) {
+	var ms [8]byte
+	binary.BigEndian.PutUint64(ms[:], uint64(t.UnixMilli()))
+	copy(b[:6], ms[2:])
+}
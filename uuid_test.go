@@ -0,0 +1,108 @@
+package art_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/Clement-Jean/go-art"
+)
+
+type UUID [16]byte
+
+func parseUUID(s []byte) (u UUID) {
+	hex.Decode(u[:], bytes.ReplaceAll(s, []byte("-"), nil))
+	return u
+}
+
+func TestUUIDInsertSearchDelete(t *testing.T) {
+	var uuids []UUID
+	for _, line := range loadTestFile("testdata/uuid.txt") {
+		uuids = append(uuids, parseUUID(line))
+	}
+
+	tr := art.NewUUIDTree[UUID, int]()
+	for i, u := range uuids {
+		tr.Insert(u, i)
+	}
+
+	for i, u := range uuids {
+		if v, ok := tr.Search(u); !ok || v != i {
+			t.Fatalf("expected %x to be found with %d, got %d", u, i, v)
+		}
+	}
+
+	var got []UUID
+	for k, _ := range tr.All() {
+		got = append(got, k)
+	}
+
+	slices.SortFunc(uuids, func(a, b UUID) int { return bytes.Compare(a[:], b[:]) })
+	if !slices.Equal(uuids, got) {
+		t.Fatalf("expected %d sorted uuids, got %d", len(uuids), len(got))
+	}
+
+	if s := tr.Stats(); s.KeyBytes != 0 || s.Leaves != len(uuids) {
+		t.Fatalf("expected %d leaves holding their keys, got %+v", len(uuids), s)
+	}
+
+	for _, u := range uuids[:len(uuids)/2] {
+		if !tr.Delete(u) {
+			t.Fatalf("expected %x to be deleted", u)
+		}
+	}
+
+	if tr.Size() != len(uuids)-len(uuids)/2 {
+		t.Fatalf("expected size %d, got %d", len(uuids)-len(uuids)/2, tr.Size())
+	}
+}
+
+func newUUIDv7(at time.Time, seq uint16) UUID {
+	var u UUID
+	binary.BigEndian.PutUint64(u[:8], uint64(at.UnixMilli())<<16|0x7000|uint64(seq&0x0fff))
+	binary.BigEndian.PutUint64(u[8:], 0x8000000000000000|uint64(seq))
+	return u
+}
+
+func TestUUIDv7Window(t *testing.T) {
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	tr := art.NewUUIDTree[UUID, int]()
+
+	for i := range 100 {
+		tr.Insert(newUUIDv7(base.Add(time.Duration(i)*time.Second), uint16(i)), i)
+	}
+
+	lo, hi := art.UUIDv7Bounds[UUID](base.Add(10*time.Second), base.Add(14*time.Second))
+
+	var got []int
+	for k, v := range tr.Range(lo, hi) {
+		if at := art.UUIDv7Time(k); at.Before(base.Add(10 * time.Second)) {
+			t.Fatalf("unexpected uuid created at %v", at)
+		}
+		got = append(got, v)
+	}
+
+	if expected := []int{10, 11, 12, 13, 14}; !slices.Equal(expected, got) {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+}
+
+func TestFixedBytesKeys(t *testing.T) {
+	tr := art.NewCompoundTree[[4]byte, string](art.FixedBytesKey[[4]byte]{})
+
+	tr.Insert([4]byte{10, 0, 0, 1}, "a")
+	tr.Insert([4]byte{192, 168, 0, 1}, "b")
+	tr.Insert([4]byte{10, 0, 0, 0}, "c")
+
+	var got []string
+	for _, v := range tr.All() {
+		got = append(got, v)
+	}
+
+	if expected := []string{"c", "a", "b"}; !slices.Equal(expected, got) {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+}